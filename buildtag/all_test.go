@@ -0,0 +1,366 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseExpr(t *testing.T) {
+	for i, test := range []struct {
+		src, expr, err string
+	}{
+		{"//go:build linux", "linux", ""},
+		{"//go:build linux && (amd64 || arm64) && !cgo", "linux && (amd64 || arm64) && !cgo", ""},
+		{"//go:build !(a||b)", "!(a || b)", ""},
+		{"a || b && c", "a || b && c", ""},
+		{"(a || b) && c", "(a || b) && c", ""},
+		{"// +build linux,386 darwin,!cgo", "linux && 386 || darwin && !cgo", ""},
+		{"// +build go1.21", "go1.21", ""},
+		{"//go:build", "", "1:11: unexpected end of expression"},
+		{"//go:build a &&", "", "1:16: unexpected end of expression"},
+		{"//go:build (a", "", "1:14: missing )"},
+		{"//go:build a b", "", "1:14: unexpected token \"b\""},
+		{"//go:build a & b", "", "1:14: unexpected token \"&\""},
+		{"// +build a,!!b", "", "1:13: invalid // +build term \"!!b\""},
+		{"// +build", "", "1:1: empty // +build line"},
+	} {
+		x, err := ParseExpr(test.src)
+		if g, e := fmt.Sprint(err), test.err; err != nil && g != e {
+			t.Errorf("%d: got error %q, expected %q", i, g, e)
+			continue
+		}
+
+		if err == nil && test.err != "" {
+			t.Errorf("%d: unexpected success", i)
+			continue
+		}
+
+		if err == nil {
+			if g, e := x.String(), test.expr; g != e {
+				t.Errorf("%d: got %q, expected %q", i, g, e)
+			}
+		}
+	}
+}
+
+func TestParseExprTooComplex(t *testing.T) {
+	if _, err := ParseExpr(strings.Repeat("!", maxSize-1) + "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, src := range []string{
+		strings.Repeat("!", 1e6) + "a",
+		strings.Repeat("(", 1e6) + "a" + strings.Repeat(")", 1e6),
+		"//go:build a" + strings.Repeat(" || a", maxSize),
+		"// +build a" + strings.Repeat(" a", maxSize),
+	} {
+		if _, err := ParseExpr(src); err == nil || !strings.HasSuffix(err.Error(), ": expression too complex") {
+			t.Errorf("%d: got %v", i, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for i, test := range []struct {
+		src, expr string
+	}{
+		{"package p\n", "<nil>"},
+		{"// Copyright\n\n//go:build linux\n\npackage p\n", "linux"},
+		{"\ufeff//go:build linux\n\npackage p\n", "linux"},
+		{"// +build linux\n// +build amd64\n\npackage p\n", "linux && amd64"},
+		{"//go:build linux\n// +build darwin\n\npackage p\n", "linux"},
+		{"/*\n//go:build linux\n*/\npackage p\n", "<nil>"},
+		{"package p\n\n//go:build linux\n", "<nil>"},
+		{"//go:build linux\n", "<nil>"},
+		{"// +build linux\npackage p\n", "<nil>"},
+		{"// Package p.\n// +build linux\npackage p\n", "<nil>"},
+		{"// +build linux\n\n// +build amd64\npackage p\n", "linux"},
+		{"/* c */ package p\n//go:build linux\n", "<nil>"},
+		{"/* c */ //go:build linux\n\npackage p\n", "<nil>"},
+		{"/* c\n*/ // c\n//go:build linux\npackage p\n", "linux"},
+		{"// +build linux\n\n", "<nil>"},
+	} {
+		x, err := Parse("p.go", []byte(test.src))
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+
+		if g, e := fmt.Sprint(x), test.expr; g != e {
+			t.Errorf("%d: got %q, expected %q", i, g, e)
+		}
+	}
+
+	if _, err := Parse("p.go", []byte("//go:build a\n//go:build b\npackage p\n")); fmt.Sprint(err) != "p.go:2:1: multiple //go:build lines" {
+		t.Errorf("got %v", err)
+	}
+	if _, err := Parse("p.go", []byte("\n  //go:build a ||\npackage p\n")); fmt.Sprint(err) != "p.go:2:18: unexpected end of expression" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	x, err := ParseExpr("//go:build (linux || darwin) && !cgo && go1.21")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range []struct {
+		c  Context
+		ok bool
+	}{
+		{Context{GOOS: "linux", GOARCH: "amd64", Tags: []string{"go1.21"}}, true},
+		{Context{GOOS: "android", GOARCH: "arm64", Tags: []string{"go1.21"}}, true},
+		{Context{GOOS: "linux", GOARCH: "amd64", Tags: []string{"go1.21", "cgo"}}, false},
+		{Context{GOOS: "windows", GOARCH: "amd64", Tags: []string{"go1.21"}}, false},
+		{Context{GOOS: "darwin", GOARCH: "arm64"}, false},
+	} {
+		if g, e := test.c.Eval(x), test.ok; g != e {
+			t.Errorf("%d: got %v, expected %v", i, g, e)
+		}
+	}
+
+	y, err := ParseExpr("unix")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := (Context{GOOS: "plan9"}); c.Eval(y) {
+		t.Error("plan9 is not unix")
+	}
+	if c := (Context{GOOS: "illumos"}); !c.Eval(y) {
+		t.Error("illumos is unix")
+	}
+}
+
+func ExamplePlatforms() {
+	x, err := ParseExpr("//go:build (darwin || windows) && arm64")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(Platforms(x))
+	// Output:
+	// [darwin/arm64 ios/arm64 windows/arm64]
+}
+
+func ExampleAssignments() {
+	x, err := ParseExpr("//go:build a && !b || c")
+	if err != nil {
+		panic(err)
+	}
+
+	a, err := Assignments(x)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(Tags(x))
+	for _, v := range a {
+		fmt.Println(v)
+	}
+	// Output:
+	// [a b c]
+	// map[a:true b:false c:false]
+	// map[a:false b:false c:true]
+	// map[a:true b:false c:true]
+	// map[a:false b:true c:true]
+	// map[a:true b:true c:true]
+}
+
+func TestAssignmentsLimit(t *testing.T) {
+	var a []string
+	for i := 0; i < MaxAssignmentTags; i++ {
+		a = append(a, fmt.Sprintf("t%d", i))
+	}
+	x, err := ParseExpr(strings.Join(a, " || "))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Assignments(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(r), 1<<MaxAssignmentTags-1; g != e {
+		t.Errorf("got %d assignments, expected %d", g, e)
+	}
+
+	if x, err = ParseExpr(strings.Join(append(a, "t16"), " || ")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Assignments(x); fmt.Sprint(err) != "too many tags: 17, the limit is 16" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestFileConstraint(t *testing.T) {
+	for i, test := range []struct {
+		name, expr string
+	}{
+		{"foo.go", "<nil>"},
+		{"linux.go", "<nil>"},
+		{"foo_linux.go", "linux"},
+		{"foo_amd64.go", "amd64"},
+		{"foo_windows_arm64.go", "windows && arm64"},
+		{"dir/foo_linux_amd64_test.go", "linux && amd64"},
+		{"foo_test.go", "<nil>"},
+		{"foo_linux_bar.go", "<nil>"},
+		{"foo_bar_amd64.go", "amd64"},
+		{"_linux.go", "linux"},
+	} {
+		if g, e := fmt.Sprint(FileConstraint(test.name)), test.expr; g != e {
+			t.Errorf("%d: %s: got %q, expected %q", i, test.name, g, e)
+		}
+	}
+}
+
+func TestMatchFile(t *testing.T) {
+	c := &Context{GOOS: "android", GOARCH: "arm64"}
+	for i, test := range []struct {
+		name, src string
+		ok        bool
+	}{
+		{"a.go", "package p\n", true},
+		{"a_linux.go", "package p\n", true},
+		{"a_linux_test.go", "package p\n", true},
+		{"a_darwin.go", "package p\n", false},
+		{"a_amd64.go", "package p\n", false},
+		{"a_linux.go", "//go:build !cgo\n\npackage p\n", true},
+		{"a_linux.go", "//go:build !unix\n\npackage p\n", false},
+		{"_a.go", "package p\n", false},
+		{".a.go", "package p\n", false},
+		{"a.s", "", false},
+	} {
+		ok, err := c.MatchFile(test.name, []byte(test.src))
+		if err != nil {
+			t.Errorf("%d: %v", i, err)
+			continue
+		}
+
+		if ok != test.ok {
+			t.Errorf("%d: %s: got %v, expected %v", i, test.name, ok, test.ok)
+		}
+	}
+}
+
+func TestReleaseTags(t *testing.T) {
+	a, err := ReleaseTags("go1.3.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := fmt.Sprint(a), "[go1.1 go1.2 go1.3]"; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+
+	if _, err := ReleaseTags("2.0"); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func TestMatchDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"p/a.go":              {Data: []byte("package p\n")},
+		"p/a_test.go":         {Data: []byte("package p\n")},
+		"p/b_windows.go":      {Data: []byte("package p\n")},
+		"p/cgo.go":            {Data: []byte("package p\n\n// #include <stdio.h>\nimport \"C\"\n")},
+		"p/gccgo.go":          {Data: []byte("//go:build gccgo\n\npackage p\n")},
+		"p/new.go":            {Data: []byte("//go:build go1.22\n\npackage p\n")},
+		"p/nocgo.go":          {Data: []byte("//go:build !cgo\n\npackage p\n")},
+		"p/README":            {},
+		"p/sub/x.go":          {Data: []byte("package sub\n")},
+		"p/_ignored_linux.go": {Data: []byte("package p\n")},
+	}
+	tags, err := ReleaseTags("1.21")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Context{GOOS: "linux", GOARCH: "amd64", Compiler: "gc", ReleaseTags: tags}
+	a, err := c.MatchDir(fsys, "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := fmt.Sprint(a), "[a.go nocgo.go]"; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+
+	c.CgoEnabled = true
+	c.Compiler = "gccgo"
+	c.ReleaseTags = append(c.ReleaseTags, "go1.22")
+	if a, err = c.MatchDir(fsys, "p"); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := fmt.Sprint(a), "[a.go cgo.go gccgo.go new.go]"; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+}
+
+func TestLint(t *testing.T) {
+	for i, test := range []struct {
+		src, lang, w string
+	}{
+		{"package p\n", "", "[]"},
+		{"//go:build a\n// +build a\n\npackage p\n", "", "[]"},
+		{"// Copyright.\n\n// +build linux darwin\n// +build !cgo\n\npackage p\n", "", "[3:1: // +build lines without //go:build line (plusbuild) 3:1 \"//go:build (linux || darwin) && !cgo\\n\"]"},
+		{"// Package p.\n// +build linux\npackage p\n", "", "[]"},
+		{"package p\n\nconst m = 0755 + 0_644 + 0 + 0o700 + 0x1f + 089.5 + 0600i\n", "", "[3:11: octal literal 0755 without 0o prefix (octal) 3:15 \"0o755\" 3:18: octal literal 0_644 without 0o prefix (octal) 3:23 \"0o_644\"]"},
+		{"package p\n\nconst m = 0755\n", "1.12", "[]"},
+		{"package p\n\nvar x interface{}\nvar y interface{ M() }\nvar z interface /* c */ {}\n", "1.18", "[3:7: interface{} can be replaced by any (any) 3:18 \"any\"]"},
+		{"package p\n\nvar x interface {\n}\n", "go1.21", "[3:7: interface{} can be replaced by any (any) 4:2 \"any\"]"},
+		{"package p\n\nvar x interface{}\n", "1.17", "[]"},
+		{"// +build a\n\npackage p\n\nvar x interface{} = 0644\n", "", "[1:1: // +build lines without //go:build line (plusbuild) 1:1 \"//go:build a\\n\" 5:7: interface{} can be replaced by any (any) 5:18 \"any\" 5:21: octal literal 0644 without 0o prefix (octal) 5:25 \"0o644\"]"},
+	} {
+		w, err := Lint("p.go", []byte(test.src), test.lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var a []string
+		for _, v := range w {
+			a = append(a, fmt.Sprintf("%v %v %q", v, v.End, v.Fix))
+		}
+		if g, e := fmt.Sprint(a), test.w; g != e {
+			t.Errorf("%d:\ngot      %s\nexpected %s", i, g, e)
+		}
+	}
+
+	src := "// +build linux\n// hello\n// +build amd64\n\npackage p\n"
+	w, err := Lint("p.go", []byte(src), "")
+	if err != nil || len(w) != 1 {
+		t.Fatal(w, err)
+	}
+
+	lines := strings.SplitAfter(src, "\n")
+	lines[w[0].Line-1] = w[0].Fix + lines[w[0].Line-1]
+	if g, e := strings.Join(lines, ""), "//go:build linux && amd64\n"+src; g != e {
+		t.Errorf("got %q, expected %q", g, e)
+	}
+
+	line := "var x interface{} = 0644"
+	if w, err = Lint("p.go", []byte("package p\n\n"+line+"\n"), ""); err != nil || len(w) != 2 {
+		t.Fatal(w, err)
+	}
+
+	for i := len(w) - 1; i >= 0; i-- {
+		line = line[:w[i].Col-1] + w[i].Fix + line[w[i].End.Col-1:]
+	}
+	if g, e := line, "var x any = 0o644"; g != e {
+		t.Errorf("got %q, expected %q", g, e)
+	}
+
+	if _, err := Lint("p.go", []byte("// +build a,\n\npackage p\n"), ""); err == nil {
+		t.Error("unexpected success")
+	}
+}
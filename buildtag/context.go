@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	goversion "github.com/cznic/parser/goversion"
+)
+
+// Context describes a build configuration, similarly to go/build.Context.
+type Context struct {
+	GOOS        string
+	GOARCH      string
+	Compiler    string   // Compiler is "gc", "gccgo" or empty.
+	CgoEnabled  bool     // CgoEnabled satisfies "cgo" and admits files importing "C".
+	ReleaseTags []string // ReleaseTags are the satisfied release tags, see ReleaseTags.
+	Tags        []string // Other satisfied tags.
+}
+
+// ReleaseTags returns the release tags satisfied by the Go version, for
+// example go1.1 through go1.22 for 1.22.3.
+func ReleaseTags(version string) ([]string, error) {
+	v, err := goversion.Parse(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Major != 1 {
+		return nil, fmt.Errorf("unsupported Go version %q", version)
+	}
+
+	var r []string
+	for i := 1; i <= v.Minor; i++ {
+		r = append(r, fmt.Sprintf("go1.%d", i))
+	}
+	return r, nil
+}
+
+// Match reports whether tag is satisfied in c. Besides GOOS, GOARCH, Compiler,
+// "cgo" if CgoEnabled, ReleaseTags and Tags, "unix" is satisfied on Unix-like
+// systems and the GOOS values implied by android (linux), illumos (solaris)
+// and ios (darwin) are satisfied as well.
+func (c *Context) Match(tag string) bool {
+	switch {
+	case tag == "":
+		return false
+	case tag == c.GOOS, tag == c.GOARCH, tag == c.Compiler:
+		return true
+	case tag == "cgo" && c.CgoEnabled:
+		return true
+	case tag == "unix":
+		return UnixOS[c.GOOS]
+	case tag == "linux" && c.GOOS == "android",
+		tag == "solaris" && c.GOOS == "illumos",
+		tag == "darwin" && c.GOOS == "ios":
+		return true
+	}
+
+	for _, v := range c.ReleaseTags {
+		if v == tag {
+			return true
+		}
+	}
+	for _, v := range c.Tags {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval reports whether x holds in c. A nil x always holds.
+func (c *Context) Eval(x Expr) bool {
+	return x == nil || x.Eval(c.Match)
+}
+
+// Platform is a GOOS/GOARCH pair.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+// String implements fmt.Stringer.
+func (p Platform) String() string { return p.GOOS + "/" + p.GOARCH }
+
+// KnownPlatforms lists the GOOS/GOARCH pairs supported by the go command, as
+// reported by 'go tool dist list'.
+var KnownPlatforms = []Platform{
+	{"aix", "ppc64"},
+	{"android", "386"}, {"android", "amd64"}, {"android", "arm"}, {"android", "arm64"},
+	{"darwin", "amd64"}, {"darwin", "arm64"},
+	{"dragonfly", "amd64"},
+	{"freebsd", "386"}, {"freebsd", "amd64"}, {"freebsd", "arm"}, {"freebsd", "arm64"},
+	{"illumos", "amd64"},
+	{"ios", "amd64"}, {"ios", "arm64"},
+	{"js", "wasm"},
+	{"linux", "386"}, {"linux", "amd64"}, {"linux", "arm"}, {"linux", "arm64"},
+	{"linux", "loong64"}, {"linux", "mips"}, {"linux", "mips64"}, {"linux", "mips64le"},
+	{"linux", "mipsle"}, {"linux", "ppc64"}, {"linux", "ppc64le"}, {"linux", "riscv64"},
+	{"linux", "s390x"},
+	{"netbsd", "386"}, {"netbsd", "amd64"}, {"netbsd", "arm"}, {"netbsd", "arm64"},
+	{"openbsd", "386"}, {"openbsd", "amd64"}, {"openbsd", "arm"}, {"openbsd", "arm64"},
+	{"openbsd", "ppc64"}, {"openbsd", "riscv64"},
+	{"plan9", "386"}, {"plan9", "amd64"}, {"plan9", "arm"},
+	{"solaris", "amd64"},
+	{"wasip1", "wasm"},
+	{"windows", "386"}, {"windows", "amd64"}, {"windows", "arm64"},
+}
+
+// UnixOS is the set of GOOS values satisfying the "unix" tag.
+var UnixOS = map[string]bool{
+	"aix":       true,
+	"android":   true,
+	"darwin":    true,
+	"dragonfly": true,
+	"freebsd":   true,
+	"hurd":      true,
+	"illumos":   true,
+	"ios":       true,
+	"linux":     true,
+	"netbsd":    true,
+	"openbsd":   true,
+	"solaris":   true,
+}
+
+// Platforms returns the known platforms for which x holds when the additional
+// tags are satisfied.
+func Platforms(x Expr, tags ...string) (r []Platform) {
+	for _, v := range KnownPlatforms {
+		c := &Context{GOOS: v.GOOS, GOARCH: v.GOARCH, Tags: tags}
+		if c.Eval(x) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// Tags returns the sorted list of distinct tags mentioned in x.
+func Tags(x Expr) []string {
+	m := map[string]bool{}
+	if x != nil {
+		x.Eval(func(tag string) bool {
+			m[tag] = true
+			return false
+		})
+	}
+	r := make([]string, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// Assignment maps tags to their truth values.
+type Assignment map[string]bool
+
+// MaxAssignmentTags is the maximum number of distinct tags of an expression
+// passed to Assignments. It bounds the result to 2^16 assignments.
+const MaxAssignmentTags = 16
+
+// Assignments returns every assignment of truth values to the tags of x for
+// which x holds. Tags are treated as independent variables, so the number of
+// candidate assignments is 2^len(Tags(x)). Assignments fails if x mentions
+// more than MaxAssignmentTags tags.
+func Assignments(x Expr) (r []Assignment, err error) {
+	tags := Tags(x)
+	if len(tags) > MaxAssignmentTags {
+		return nil, fmt.Errorf("too many tags: %d, the limit is %d", len(tags), MaxAssignmentTags)
+	}
+
+	bit := make(map[string]uint, len(tags))
+	for i, v := range tags {
+		bit[v] = uint(i)
+	}
+	for i := 0; i < 1<<uint(len(tags)); i++ {
+		if x != nil && !x.Eval(func(tag string) bool { return i&(1<<bit[tag]) != 0 }) {
+			continue
+		}
+
+		a := make(Assignment, len(tags))
+		for j, v := range tags {
+			a[v] = i&(1<<uint(j)) != 0
+		}
+		r = append(r, a)
+	}
+	return r, nil
+}
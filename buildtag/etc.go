@@ -0,0 +1,486 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser and evaluator for Go build
+// constraints[0].
+//
+// Both the //go:build form and the legacy // +build form are parsed into the
+// same expression AST. Context evaluates an expression for a particular build
+// configuration, Platforms lists the known GOOS/GOARCH pairs for which an
+// expression holds and Assignments enumerates the satisfying assignments of
+// the tags an expression mentions. FileConstraint derives the constraint
+// implied by a file name suffix, such as _linux_amd64.go, Context.MatchFile
+// combines it with the constraint in the file header and Context.MatchDir
+// selects the files of a directory a build would compile. Lint reports
+// superseded syntax, such as file headers still relying on legacy // +build
+// lines.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://pkg.go.dev/cmd/go#hdr-Build_constraints
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col }
+
+// Expr is a build constraint expression, one of *TagExpr, *NotExpr, *AndExpr
+// or *OrExpr.
+type Expr interface {
+	// Eval reports whether the expression holds when the tags for which ok
+	// returns true are set.
+	Eval(ok func(tag string) bool) bool
+	// String returns the expression in //go:build syntax.
+	String() string
+	isExpr()
+}
+
+// TagExpr is a single tag.
+type TagExpr struct {
+	Pos
+	Tag string
+}
+
+// NotExpr is !X.
+type NotExpr struct {
+	Pos
+	X Expr
+}
+
+// AndExpr is X && Y.
+type AndExpr struct {
+	X, Y Expr
+}
+
+// OrExpr is X || Y.
+type OrExpr struct {
+	X, Y Expr
+}
+
+func (*TagExpr) isExpr() {}
+func (*NotExpr) isExpr() {}
+func (*AndExpr) isExpr() {}
+func (*OrExpr) isExpr()  {}
+
+// Eval implements Expr.
+func (x *TagExpr) Eval(ok func(string) bool) bool { return ok(x.Tag) }
+
+// Eval implements Expr.
+func (x *NotExpr) Eval(ok func(string) bool) bool { return !x.X.Eval(ok) }
+
+// Eval implements Expr.
+func (x *AndExpr) Eval(ok func(string) bool) bool {
+	// Evaluate both sides so ok observes every tag, like go/build does.
+	a := x.X.Eval(ok)
+	b := x.Y.Eval(ok)
+	return a && b
+}
+
+// Eval implements Expr.
+func (x *OrExpr) Eval(ok func(string) bool) bool {
+	a := x.X.Eval(ok)
+	b := x.Y.Eval(ok)
+	return a || b
+}
+
+// String implements fmt.Stringer.
+func (x *TagExpr) String() string { return x.Tag }
+
+// String implements fmt.Stringer.
+func (x *NotExpr) String() string {
+	switch x.X.(type) {
+	case *AndExpr, *OrExpr:
+		return "!(" + x.X.String() + ")"
+	default:
+		return "!" + x.X.String()
+	}
+}
+
+// String implements fmt.Stringer.
+func (x *AndExpr) String() string { return andArg(x.X) + " && " + andArg(x.Y) }
+
+// String implements fmt.Stringer.
+func (x *OrExpr) String() string { return x.X.String() + " || " + x.Y.String() }
+
+func andArg(x Expr) string {
+	if _, ok := x.(*OrExpr); ok {
+		return "(" + x.String() + ")"
+	}
+
+	return x.String()
+}
+
+// Parse returns the build constraint of the Go source file fname with content
+// src. Only the file header, ie. the comments and empty lines preceding the
+// package clause, is examined and // +build lines must be followed by a blank
+// line, as in go/build. A //go:build line takes precedence over // +build
+// lines, which are combined using &&. The returned expression is nil if the
+// file has no build constraint. If the constraint couldn't be parsed, the
+// error is an ErrorList indicating the specific failures.
+func Parse(fname string, src []byte, opts ...Option) (Expr, error) {
+	lines, ok := header(src)
+	var goBuild Expr
+	var plusBuild []Expr
+	errs := errlist.New[Pos](fname, opts)
+	for _, v := range lines {
+		x := parseLine(errs, v.Line, v.Col, v.text)
+		if x == nil {
+			continue
+		}
+
+		switch {
+		case IsPlusBuild(v.text):
+			plusBuild = append(plusBuild, x)
+		case goBuild != nil:
+			errs.Errorf(v.Pos, "multiple //go:build lines")
+		default:
+			goBuild = x
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if !ok { // No package clause.
+		return nil, nil
+	}
+
+	if goBuild != nil || len(plusBuild) == 0 {
+		return goBuild, nil
+	}
+
+	return andAll(plusBuild), nil
+}
+
+func andAll(a []Expr) Expr {
+	x := a[0]
+	for _, v := range a[1:] {
+		x = &AndExpr{x, v}
+	}
+	return x
+}
+
+type headerLine struct {
+	Pos
+	text string // Trimmed line.
+}
+
+// header returns the //go:build and // +build lines of the file header of src
+// and whether the header is followed by a package clause. The rules are those
+// of go/build: the header ends at the first text outside of comments and
+// // +build lines count only if they precede the last blank line of the
+// header.
+func header(src []byte) (r []headerLine, ok bool) {
+	lastBlank := 0     // Line number of the last blank line of the header.
+	ended := false     // A line not starting with // was seen.
+	inComment := false // Within a /* */ comment.
+Lines:
+	for i, line := range strings.Split(string(src), "\n") {
+		s := strings.TrimSpace(line)
+		if i == 0 {
+			s = strings.TrimPrefix(s, "\ufeff") // Byte order mark.
+		}
+		if s == "" && !ended {
+			lastBlank = i + 1
+			continue
+		}
+
+		if !strings.HasPrefix(s, "//") {
+			ended = true
+		}
+
+		if !inComment && IsGoBuild(s) || IsPlusBuild(s) {
+			col := strings.Index(line, s) + 1
+			r = append(r, headerLine{Pos{i + 1, col}, s})
+		}
+
+		for s != "" {
+			switch {
+			case inComment:
+				j := strings.Index(s, "*/")
+				if j < 0 {
+					continue Lines
+				}
+
+				inComment = false
+				s = strings.TrimSpace(s[j+2:])
+			case strings.HasPrefix(s, "//"):
+				continue Lines
+			case strings.HasPrefix(s, "/*"):
+				inComment = true
+				s = strings.TrimSpace(s[2:])
+			default:
+				ok = true
+				break Lines
+			}
+		}
+	}
+	w := 0
+	for _, v := range r {
+		if IsGoBuild(v.text) || v.Line < lastBlank {
+			r[w] = v
+			w++
+		}
+	}
+	return r[:w], ok
+}
+
+// IsGoBuild reports whether line is a //go:build constraint.
+func IsGoBuild(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "//go:build") && (len(line) == len("//go:build") || line[len("//go:build")] == ' ' || line[len("//go:build")] == '\t')
+}
+
+// IsPlusBuild reports whether line is a legacy // +build constraint.
+func IsPlusBuild(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "//") {
+		return false
+	}
+
+	line = strings.TrimSpace(line[2:])
+	return strings.HasPrefix(line, "+build") && (len(line) == len("+build") || line[len("+build")] == ' ' || line[len("+build")] == '\t')
+}
+
+// ParseExpr parses a single //go:build or // +build line, or a bare //go:build
+// expression, and returns the corresponding AST.
+func ParseExpr(line string) (Expr, error) {
+	line = strings.TrimSpace(line)
+	errs := errlist.New[Pos]("", nil)
+	x := parseLine(errs, 1, 1, line)
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+// parseLine parses the constraint line s at line and col. Errors are recorded
+// in errs, in which case the result is nil.
+func parseLine(errs *errlist.Errors[Pos], line, col int, s string) Expr {
+	p := &exprParser{errs: errs, line: line, col: col}
+	switch {
+	case IsGoBuild(s):
+		p.src, p.off = s, len("//go:build")
+	case IsPlusBuild(s):
+		return p.plusBuild(s)
+	default:
+		p.src = s
+	}
+	x := p.or()
+	if p.next(); p.tok != "" {
+		p.errorf("unexpected token %q", p.tok)
+	}
+	if p.failed {
+		return nil
+	}
+
+	return x
+}
+
+// maxSize limits the number of terms of an expression. It prevents exhausting
+// the stack while parsing or evaluating deeply nested expressions.
+const maxSize = 1000
+
+type exprParser struct {
+	errs   *errlist.Errors[Pos]
+	failed bool // An error was reported.
+	size   int  // Number of terms parsed so far.
+	line   int
+	col    int
+	src    string
+	off    int
+	tok    string // Current token, "" at EOF.
+	pos    Pos    // Position of tok.
+	ahead  bool   // tok was peeked.
+}
+
+func (p *exprParser) errorf(s string, va ...interface{}) {
+	if !p.failed { // Report only the first error of a line.
+		p.failed = true
+		p.errs.Errorf(p.pos, s, va...)
+	}
+}
+
+func isTagChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c >= 0x80
+}
+
+// next sets tok to the next token.
+func (p *exprParser) next() {
+	if p.ahead {
+		p.ahead = false
+		return
+	}
+
+	for p.off < len(p.src) && (p.src[p.off] == ' ' || p.src[p.off] == '\t') {
+		p.off++
+	}
+	p.pos = Pos{p.line, p.col + p.off}
+	if p.off >= len(p.src) {
+		p.tok = ""
+		return
+	}
+
+	start := p.off
+	switch c := p.src[p.off]; {
+	case c == '(' || c == ')' || c == '!':
+		p.off++
+	case strings.HasPrefix(p.src[p.off:], "&&") || strings.HasPrefix(p.src[p.off:], "||"):
+		p.off += 2
+	case isTagChar(c):
+		for p.off < len(p.src) && isTagChar(p.src[p.off]) {
+			p.off++
+		}
+	default:
+		p.off++
+	}
+	p.tok = p.src[start:p.off]
+}
+
+func (p *exprParser) peek() string {
+	p.next()
+	p.ahead = true
+	return p.tok
+}
+
+func (p *exprParser) or() Expr {
+	x := p.and()
+	for p.peek() == "||" {
+		p.next()
+		x = &OrExpr{x, p.and()}
+	}
+	return x
+}
+
+func (p *exprParser) and() Expr {
+	x := p.not()
+	for p.peek() == "&&" {
+		p.next()
+		x = &AndExpr{x, p.not()}
+	}
+	return x
+}
+
+func (p *exprParser) not() Expr {
+	p.next()
+	if p.size++; p.size > maxSize {
+		p.errorf("expression too complex")
+		return &TagExpr{p.pos, ""}
+	}
+
+	switch pos := p.pos; {
+	case p.tok == "!":
+		return &NotExpr{pos, p.not()}
+	case p.tok == "(":
+		x := p.or()
+		if p.next(); p.tok != ")" {
+			p.errorf("missing )")
+		}
+		return x
+	case p.tok != "" && isTagChar(p.tok[0]):
+		return &TagExpr{pos, p.tok}
+	case p.tok == "":
+		p.errorf("unexpected end of expression")
+	default:
+		p.errorf("unexpected token %q", p.tok)
+	}
+	return &TagExpr{p.pos, ""}
+}
+
+// plusBuild parses a // +build line: an OR of space separated terms, each an
+// AND of comma separated, optionally negated tags.
+func (p *exprParser) plusBuild(s string) Expr {
+	off := strings.Index(s, "+build") + len("+build")
+	var x Expr
+	for _, f := range strings.Fields(s[off:]) {
+		i := strings.Index(s[off:], f)
+		off += i
+		var y Expr
+		for _, t := range strings.Split(f, ",") {
+			p.pos = Pos{p.line, p.col + off}
+			if p.size++; p.size > maxSize {
+				p.errorf("expression too complex")
+				return nil
+			}
+
+			var z Expr
+			switch {
+			case strings.HasPrefix(t, "!!") || t == "!" || t == "":
+				p.errorf("invalid // +build term %q", t)
+			case t[0] == '!':
+				z = &NotExpr{p.pos, &TagExpr{Pos{p.line, p.col + off + 1}, t[1:]}}
+			default:
+				z = &TagExpr{p.pos, t}
+			}
+			for i := 0; i < len(t); i++ {
+				if !isTagChar(t[i]) && !(i == 0 && t[i] == '!') {
+					p.errorf("invalid // +build term %q", t)
+					break
+				}
+			}
+			off += len(t) + 1
+			if z == nil {
+				continue
+			}
+
+			if y == nil {
+				y = z
+				continue
+			}
+
+			y = &AndExpr{y, z}
+		}
+		off-- // No comma after the last term.
+		if y == nil {
+			continue
+		}
+
+		if x == nil {
+			x = y
+			continue
+		}
+
+		x = &OrExpr{x, y}
+	}
+	if x == nil {
+		p.pos = Pos{p.line, p.col}
+		p.errorf("empty // +build line")
+	}
+	if p.failed {
+		return nil
+	}
+
+	return x
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
+
+// Option configures a parse.
+type Option = errlist.Option
+
+// MaxErrors returns an Option that stops parsing once n errors are collected.
+// Zero, the default, means no limit.
+func MaxErrors(n int) Option { return errlist.MaxErrors(n) }
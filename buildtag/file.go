@@ -0,0 +1,186 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KnownOS is the set of GOOS values recognized in file name suffixes.
+var KnownOS = map[string]bool{
+	"aix":       true,
+	"android":   true,
+	"darwin":    true,
+	"dragonfly": true,
+	"freebsd":   true,
+	"hurd":      true,
+	"illumos":   true,
+	"ios":       true,
+	"js":        true,
+	"linux":     true,
+	"nacl":      true,
+	"netbsd":    true,
+	"openbsd":   true,
+	"plan9":     true,
+	"solaris":   true,
+	"wasip1":    true,
+	"windows":   true,
+	"zos":       true,
+}
+
+// KnownArch is the set of GOARCH values recognized in file name suffixes.
+var KnownArch = map[string]bool{
+	"386":         true,
+	"amd64":       true,
+	"amd64p32":    true,
+	"arm":         true,
+	"armbe":       true,
+	"arm64":       true,
+	"arm64be":     true,
+	"loong64":     true,
+	"mips":        true,
+	"mipsle":      true,
+	"mips64":      true,
+	"mips64le":    true,
+	"mips64p32":   true,
+	"mips64p32le": true,
+	"ppc":         true,
+	"ppc64":       true,
+	"ppc64le":     true,
+	"riscv":       true,
+	"riscv64":     true,
+	"s390":        true,
+	"s390x":       true,
+	"sparc":       true,
+	"sparc64":     true,
+	"wasm":        true,
+}
+
+// IsTest reports whether the file name denotes a test file.
+func IsTest(name string) bool {
+	return strings.HasSuffix(filepath.Base(name), "_test.go")
+}
+
+// FileConstraint returns the constraint implied by the name of a Go source
+// file, for example linux && amd64 for foo_linux_amd64.go as well as for
+// foo_linux_amd64_test.go. The part of the name up to the first underscore
+// never contributes to the constraint, so linux.go has none. The result is nil
+// if the name implies no constraint.
+func FileConstraint(name string) Expr {
+	name = filepath.Base(name)
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	i := strings.IndexByte(name, '_')
+	if i < 0 {
+		return nil
+	}
+
+	l := strings.Split(name[i:], "_")
+	if n := len(l); n > 0 && l[n-1] == "test" {
+		l = l[:n-1]
+	}
+	n := len(l)
+	switch {
+	case n >= 2 && KnownOS[l[n-2]] && KnownArch[l[n-1]]:
+		return &AndExpr{&TagExpr{Tag: l[n-2]}, &TagExpr{Tag: l[n-1]}}
+	case n >= 1 && (KnownOS[l[n-1]] || KnownArch[l[n-1]]):
+		return &TagExpr{Tag: l[n-1]}
+	}
+	return nil
+}
+
+// MatchFile reports whether the Go source file name with content src would be
+// included in a build using c. Both the constraint implied by the file name and
+// the build constraint in the file header must hold. Files with names
+// beginning with '_' or '.' are never included and files importing "C" are
+// included only if c.CgoEnabled.
+func (c *Context) MatchFile(name string, src []byte) (bool, error) {
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, "_") || strings.HasPrefix(base, ".") || !strings.HasSuffix(base, ".go") {
+		return false, nil
+	}
+
+	if !c.Eval(FileConstraint(name)) {
+		return false, nil
+	}
+
+	x, err := Parse(name, src)
+	if err != nil {
+		return false, err
+	}
+
+	if !c.Eval(x) {
+		return false, nil
+	}
+
+	if c.CgoEnabled {
+		return true, nil
+	}
+
+	cgo, err := importsC(name, src)
+	return !cgo, err
+}
+
+// importsC reports whether the Go source file name with content src imports
+// "C".
+func importsC(name string, src []byte) (bool, error) {
+	if !strings.Contains(string(src), `"C"`) {
+		return false, nil
+	}
+
+	f, err := goparser.ParseFile(token.NewFileSet(), name, src, goparser.ImportsOnly)
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range f.Imports {
+		if p, _ := strconv.Unquote(v.Path.Value); p == "C" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MatchDir returns the sorted names of the Go source files in dir of fsys
+// that a build using c would compile, ie. the files accepted by MatchFile.
+// Test files are not included.
+func (c *Context) MatchDir(fsys fs.FS, dir string) ([]string, error) {
+	a, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var r []string
+	for _, v := range a {
+		name := v.Name()
+		if !v.Type().IsRegular() || !strings.HasSuffix(name, ".go") || IsTest(name) {
+			continue
+		}
+
+		src, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := c.MatchFile(path.Join(dir, name), src)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			r = append(r, name)
+		}
+	}
+	sort.Strings(r)
+	return r, nil
+}
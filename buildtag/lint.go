@@ -0,0 +1,155 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+
+	goversion "github.com/cznic/parser/goversion"
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Warning codes reported by Lint.
+const (
+	PlusBuildOnly  = "plusbuild" // // +build lines without a //go:build line.
+	OctalLiteral   = "octal"     // Octal literal without the 0o prefix.
+	EmptyInterface = "any"       // interface{} where any is available.
+)
+
+// Warning describes legal but superseded syntax. Replacing the text between Pos
+// and End with Fix makes the warning go away. If Pos and End are equal, Fix is
+// to be inserted at Pos.
+type Warning struct {
+	Pos
+	End  Pos    // Position after the last character of the offending text.
+	Code string // Code is one of the warning codes.
+	Msg  string
+	Fix  string // Fix is the suggested replacement.
+}
+
+// String implements fmt.Stringer.
+func (w *Warning) String() string { return fmt.Sprintf("%v: %s (%s)", w.Pos, w.Msg, w.Code) }
+
+// Lint returns the warnings for the Go source file fname with content src.
+// lang is the Go language version of the file, for example "1.21" as in the go
+// directive of its go.mod file. An empty lang means the latest version. The
+// reported warnings are
+//
+//   - PlusBuildOnly for legacy // +build lines in a file header without a
+//     //go:build line. The go command still evaluates such lines, but gofmt
+//     and go vet expect the equivalent //go:build line, which Fix inserts
+//     before the first // +build line.
+//   - OctalLiteral for octal integer literals written with a leading 0 only,
+//     as in 0755, if lang is at least 1.13. Fix is the literal with the 0o
+//     prefix.
+//   - EmptyInterface for interface{} if lang is at least 1.18. Fix is any.
+//     Lint works on tokens and doesn't resolve identifiers, so it also
+//     suggests any in the rare files redeclaring it.
+//
+// Warnings are sorted by position. If the header couldn't be parsed, the error
+// is an ErrorList indicating the specific failures. Other syntax errors are
+// ignored.
+func Lint(fname string, src []byte, lang string, opts ...Option) (r []*Warning, err error) {
+	lines, _ := header(src)
+	var plusBuild []Expr
+	var first *headerLine
+	errs := errlist.New[Pos](fname, opts)
+	goBuild := false
+	for i, v := range lines {
+		x := parseLine(errs, v.Line, v.Col, v.text)
+		if x == nil {
+			continue
+		}
+
+		if IsGoBuild(v.text) {
+			goBuild = true
+			continue
+		}
+
+		if first == nil {
+			first = &lines[i]
+		}
+		plusBuild = append(plusBuild, x)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if !goBuild && len(plusBuild) != 0 {
+		r = append(r, &Warning{
+			Pos:  Pos{first.Line, 1},
+			End:  Pos{first.Line, 1},
+			Code: PlusBuildOnly,
+			Msg:  "// +build lines without //go:build line",
+			Fix:  "//go:build " + andAll(plusBuild).String() + "\n",
+		})
+	}
+	return append(r, lintTokens(fname, src, lang)...), nil
+}
+
+// lintTokens returns the OctalLiteral and EmptyInterface warnings of src.
+func lintTokens(fname string, src []byte, lang string) (r []*Warning) {
+	octal := lang == "" || goversion.Compare(lang, "1.13") >= 0
+	useAny := lang == "" || goversion.Compare(lang, "1.18") >= 0
+	if !octal && !useAny {
+		return nil
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(fname, -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+	position := func(p token.Pos) Pos {
+		q := fset.PositionFor(p, false)
+		return Pos{q.Line, q.Column}
+	}
+	var prev, prev2 token.Token // The two tokens preceding tok.
+	var iface token.Pos         // Position of the last interface keyword.
+	for {
+		pos, tok, lit := s.Scan()
+		switch {
+		case tok == token.EOF:
+			return r
+		case tok == token.INT && octal && isLegacyOctal(lit):
+			p := position(pos)
+			r = append(r, &Warning{
+				Pos:  p,
+				End:  Pos{p.Line, p.Col + len(lit)},
+				Code: OctalLiteral,
+				Msg:  fmt.Sprintf("octal literal %s without 0o prefix", lit),
+				Fix:  "0o" + lit[1:],
+			})
+		case tok == token.INTERFACE:
+			iface = pos
+		case tok == token.RBRACE && useAny && prev == token.LBRACE && prev2 == token.INTERFACE:
+			p := position(pos)
+			r = append(r, &Warning{
+				Pos:  position(iface),
+				End:  Pos{p.Line, p.Col + 1},
+				Code: EmptyInterface,
+				Msg:  "interface{} can be replaced by any",
+				Fix:  "any",
+			})
+		}
+		prev, prev2 = tok, prev
+	}
+}
+
+// isLegacyOctal reports whether the integer literal lit is an octal number
+// without the 0o prefix.
+func isLegacyOctal(lit string) bool {
+	if len(lit) < 2 || lit[0] != '0' {
+		return false
+	}
+
+	for i := 1; i < len(lit); i++ {
+		if c := lit[i]; (c < '0' || c > '7') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testSrc = `package p
+
+import "embed"
+
+//go:embed hello.txt
+var s string
+
+var (
+	//go:embed static/* "a b.txt"
+	//go:embed all:tmpl
+	files embed.FS
+
+	//go:embed ` + "`hello.txt`" + `
+	b []byte
+)
+`
+
+func TestParse(t *testing.T) {
+	d, err := Parse("p.go", []byte(testSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var a []string
+	for _, v := range d {
+		var p []string
+		for _, v := range v.Patterns {
+			p = append(p, fmt.Sprintf("%v %q", v.Pos, v.Value))
+		}
+		a = append(a, fmt.Sprintf("%v: %s@%v [%s]", v.Pos, v.Var, v.VarPos, strings.Join(p, ", ")))
+	}
+	if g, e := strings.Join(a, "\n"), `5:1: s@6:5 [5:12 "hello.txt"]
+9:2: files@11:2 [9:13 "static/*", 9:22 "a b.txt"]
+10:2: files@11:2 [10:13 "all:tmpl"]
+13:2: b@14:2 [13:13 "hello.txt"]`; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, test := range []struct {
+		src, err string
+	}{
+		{"package p; import _ \"embed\"\n//go:embed ../x\nvar x string\n", `p.go:2:12: pattern ../x: invalid pattern syntax: path element ".."`},
+		{"package p; import _ \"embed\"\n//go:embed /x\nvar x string\n", "p.go:2:12: pattern /x: invalid pattern syntax: leading slash"},
+		{"package p; import _ \"embed\"\n//go:embed x/\nvar x string\n", "p.go:2:12: pattern x/: invalid pattern syntax: trailing slash"},
+		{"package p; import _ \"embed\"\n//go:embed [\nvar x string\n", "p.go:2:12: pattern [: invalid pattern syntax: syntax error in pattern"},
+		{"package p; import _ \"embed\"\n//go:embed \"x\nvar x string\n", `p.go:2:1: invalid quoted string in //go:embed: "x`},
+		{"package p; import _ \"embed\"\n//go:embed\nvar x string\n", "p.go:2:1: usage: //go:embed pattern..."},
+		{"package p; import _ \"embed\"\n//go:embed x\nfunc f() {}\n", "p.go:2:1: misplaced go:embed directive"},
+		{"package p; import _ \"embed\"\nfunc f() {\n//go:embed x\nvar x string\n}\n", "p.go:3:1: go:embed cannot apply to var inside func"},
+		{"package p; import _ \"embed\"\nvar x string //go:embed a\nvar y string\n", "p.go:2:14: misplaced compiler directive"},
+		{"package p; import _ \"embed\"\n//go:embed x\nvar x, y string\n", "p.go:2:1: go:embed cannot apply to multiple vars"},
+		{"package p; import _ \"embed\"\n//go:embed x\nvar x = \"s\"\n", "p.go:2:1: go:embed cannot apply to var with initializer"},
+		{"package p; import _ \"embed\"\n//go:embed x\nvar x string = \"s\"\n", "p.go:2:1: go:embed cannot apply to var with initializer"},
+		{"package p; import _ \"embed\"\n//go:embed x\nvar (\n\tx string\n)\n", "p.go:2:1: misplaced go:embed directive"},
+		{"package p; import _ \"embed\"\nvar (\n\t//go:embed x\n\tx, y string\n)\n", "p.go:3:2: go:embed cannot apply to multiple vars"},
+		{"package p\n//go:embed x\nvar x string\n", `p.go:2:1: go:embed only allowed in Go files that import "embed"`},
+		{"package p\nimport \"fmt\"\nvar (\n\t//go:embed x\n\tx, y string\n)\n", `p.go:4:2: go:embed only allowed in Go files that import "embed"`},
+		{"package p\nimport (\n\t\"fmt\"\n\t_ \"embed\"\n)\n//go:embed x\nvar x string\n", "<nil>"},
+		{"package p\nimport e \"embed\"\n//go:embed x\nvar x e.FS\n", "<nil>"},
+	} {
+		_, err := Parse("p.go", []byte(test.src))
+		if g, e := fmt.Sprint(err), test.err; g != e {
+			t.Errorf("%d: got %q, expected %q", i, g, e)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello.txt":          {},
+		"a b.txt":            {},
+		"static/x.css":       {},
+		"static/.hidden":     {},
+		"tmpl/_base.tmpl":    {},
+		"tmpl/page.tmpl":     {},
+		"tmpl/sub/go.mod":    {},
+		"tmpl/sub/skip.tmpl": {},
+		"empty/.keep":        {},
+	}
+	d, err := Parse("p.go", []byte(testSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, e := range []string{
+		"[hello.txt]",
+		"[a b.txt static/.hidden static/x.css]", // Explicit matches include dot files.
+		"[tmpl/_base.tmpl tmpl/page.tmpl]",
+		"[hello.txt]",
+	} {
+		a, err := d[i].Match(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if g := fmt.Sprint(a); g != e {
+			t.Errorf("%d: got %s, expected %s", i, g, e)
+		}
+	}
+
+	for _, test := range []struct {
+		pattern, err string
+	}{
+		{"nothing*", "pattern nothing*: no matching files found"},
+		{"empty", "pattern empty: cannot embed directory empty: contains no embeddable files"},
+	} {
+		if _, err := (Pattern{Value: test.pattern}).Match(fsys); fmt.Sprint(err) != test.err {
+			t.Errorf("got %v, expected %s", err, test.err)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for //go:embed[0] directives.
+//
+// Parse extracts the directives of a Go source file, splits them into
+// patterns, validates the patterns and associates every directive with the
+// variable it applies to. Directive.Match evaluates the patterns against a
+// file system the same way the go command does when building the package.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://pkg.go.dev/embed
+package parser
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col }
+
+// Pattern is a single pattern of a //go:embed directive.
+type Pattern struct {
+	Pos
+	Value string // Value is unquoted and includes any "all:" prefix.
+}
+
+// All reports whether the pattern has the "all:" prefix, which includes files
+// beginning with '.' or '_' when matching directories.
+func (p Pattern) All() bool { return strings.HasPrefix(p.Value, "all:") }
+
+// Path returns the pattern without any "all:" prefix.
+func (p Pattern) Path() string { return strings.TrimPrefix(p.Value, "all:") }
+
+// Directive describes a single parsed //go:embed line.
+type Directive struct {
+	Pos
+	Patterns []Pattern
+	Var      string // Var is the name of the variable the directive applies to.
+	VarPos   Pos
+}
+
+// Parse parses the Go source file fname with content src and returns its
+// //go:embed directives. If the directives couldn't be parsed, the returned
+// directives are nil and the error is an ErrorList indicating the specific
+// failures. Parse reports only errors related to //go:embed directives.
+func Parse(fname string, src []byte, opts ...Option) ([]*Directive, error) {
+	fset := token.NewFileSet()
+	file := fset.AddFile(fname, -1, len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+	position := func(p token.Pos) Pos {
+		q := fset.Position(p)
+		return Pos{q.Line, q.Column}
+	}
+	var d, pending, spec []*Directive
+	errs := errlist.New[Pos](fname, opts)
+	var prev token.Token
+	report := func(a []*Directive, msg string) {
+		for _, v := range a {
+			errs.Errorf(v.Pos, "%s", msg)
+		}
+	}
+	braces := 0            // Nesting level of {}.
+	group := 0             // Nesting level of () within a var group.
+	depth := 0             // Nesting level of brackets within the var spec of spec.
+	imports := 0           // 1 within an import declaration, 2 within an import group.
+	embedImported := false // The file imports "embed".
+	for !errs.Full() {
+		pos, tok, lit := s.Scan()
+		if tok == token.COMMENT {
+			if !strings.HasPrefix(lit, "//go:embed") {
+				continue
+			}
+
+			rest := lit[len("//go:embed"):]
+			if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+				continue
+			}
+
+			x := &Directive{Pos: position(pos)}
+			if !startsLine(src, file.Offset(pos)) {
+				errs.Errorf(x.Pos, "misplaced compiler directive")
+				continue
+			}
+
+			var err error
+			if x.Patterns, err = parsePatterns(rest, Pos{x.Line, x.Col + len("//go:embed")}); err != nil {
+				errs.Errorf(x.Pos, "%v", err)
+				continue
+			}
+
+			if len(x.Patterns) == 0 {
+				errs.Errorf(x.Pos, "usage: //go:embed pattern...")
+				continue
+			}
+
+			for _, v := range x.Patterns {
+				if err := checkPattern(v.Path()); err != nil {
+					errs.Errorf(v.Pos, "pattern %s: %v", v.Value, err)
+				}
+			}
+			pending = append(pending, x)
+			continue
+		}
+
+		if len(spec) != 0 {
+			// Check the remainder of the var spec the directives in spec
+			// apply to, up to the end of the spec.
+			switch tok {
+			case token.LPAREN, token.LBRACK, token.LBRACE:
+				depth++
+			case token.RPAREN, token.RBRACK, token.RBRACE:
+				if depth != 0 {
+					depth--
+					break
+				}
+
+				d = append(d, spec...)
+				spec = nil
+			case token.COMMA:
+				if depth == 0 {
+					report(spec, "go:embed cannot apply to multiple vars")
+					spec = nil
+				}
+			case token.ASSIGN:
+				if depth == 0 {
+					report(spec, "go:embed cannot apply to var with initializer")
+					spec = nil
+				}
+			case token.SEMICOLON, token.EOF:
+				if depth == 0 {
+					d = append(d, spec...)
+					spec = nil
+				}
+			}
+		}
+
+		if len(pending) != 0 && !(tok == token.SEMICOLON && lit == "\n") && tok != token.VAR {
+			switch {
+			case tok == token.IDENT && !embedImported && (prev == token.VAR || group == 1):
+				report(pending, "go:embed only allowed in Go files that import \"embed\"")
+			case tok == token.IDENT && braces != 0 && (prev == token.VAR || group == 1):
+				report(pending, "go:embed cannot apply to var inside func")
+			case tok == token.IDENT && (prev == token.VAR || group == 1 && (prev == token.LPAREN || prev == token.SEMICOLON)):
+				for _, v := range pending {
+					v.Var, v.VarPos = lit, position(pos)
+				}
+				spec, depth = pending, 0
+			default:
+				report(pending, "misplaced go:embed directive")
+			}
+			pending = nil
+		}
+
+		switch tok {
+		case token.EOF:
+			if err := errs.Err(); err != nil {
+				return nil, err
+			}
+
+			return d, nil
+		case token.IMPORT:
+			imports = 1
+		case token.STRING:
+			if imports != 0 {
+				if path, err := strconv.Unquote(lit); err == nil && path == "embed" {
+					embedImported = true
+				}
+			}
+		case token.SEMICOLON:
+			if imports == 1 {
+				imports = 0
+			}
+		case token.LBRACE:
+			braces++
+		case token.RBRACE:
+			braces--
+		case token.LPAREN:
+			switch {
+			case prev == token.IMPORT:
+				imports = 2
+			case group != 0:
+				group++
+			case prev == token.VAR:
+				group = 1
+			}
+		case token.RPAREN:
+			if group != 0 {
+				group--
+			}
+			if imports == 2 {
+				imports = 0
+			}
+		}
+		prev = tok
+	}
+	return nil, errs.Err()
+}
+
+// startsLine reports whether only white space precedes src[off] on its line.
+func startsLine(src []byte, off int) bool {
+	for off--; off >= 0 && src[off] != '\n'; off-- {
+		if src[off] != ' ' && src[off] != '\t' && src[off] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePatterns splits the space separated, optionally quoted patterns of s.
+// pos is the position of s.
+func parsePatterns(s string, pos Pos) (r []Pattern, err error) {
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case ' ', '\t':
+			i++
+		case '"', '`':
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if c == '"' && s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", s[i:])
+			}
+
+			v, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string in //go:embed: %s", s[i:j+1])
+			}
+
+			r = append(r, Pattern{Pos{pos.Line, pos.Col + i}, v})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && s[j] != ' ' && s[j] != '\t' {
+				j++
+			}
+			r = append(r, Pattern{Pos{pos.Line, pos.Col + i}, s[i:j]})
+			i = j
+		}
+	}
+	return r, nil
+}
+
+// checkPattern validates the pattern p, without any "all:" prefix.
+func checkPattern(p string) error {
+	switch {
+	case p == "":
+		return fmt.Errorf("empty pattern")
+	case p == ".":
+		return fmt.Errorf("invalid pattern syntax: cannot embed the package directory itself")
+	case strings.HasPrefix(p, "/"):
+		return fmt.Errorf("invalid pattern syntax: leading slash")
+	case strings.HasSuffix(p, "/"):
+		return fmt.Errorf("invalid pattern syntax: trailing slash")
+	}
+
+	for _, v := range strings.Split(p, "/") {
+		switch v {
+		case "":
+			return fmt.Errorf("invalid pattern syntax: empty path element")
+		case ".", "..":
+			return fmt.Errorf("invalid pattern syntax: path element %q", v)
+		}
+	}
+
+	if _, err := path.Match(p, ""); err != nil {
+		return fmt.Errorf("invalid pattern syntax: %v", err)
+	}
+
+	return nil
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
+
+// Option configures a parse.
+type Option = errlist.Option
+
+// MaxErrors returns an Option that stops parsing once n errors are collected.
+// Zero, the default, means no limit.
+func MaxErrors(n int) Option { return errlist.MaxErrors(n) }
@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// Match returns the sorted list of files in fsys matched by the patterns of
+// d. fsys is rooted at the package directory. Like the go command, Match
+// reports an error for a pattern that matches no files, directories matched
+// by a pattern are included recursively except for files beginning with '.'
+// or '_' (unless the pattern has the "all:" prefix) and except for
+// subdirectories containing other modules.
+func (d *Directive) Match(fsys fs.FS) ([]string, error) {
+	m := map[string]bool{}
+	for _, v := range d.Patterns {
+		a, err := v.Match(fsys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range a {
+			m[v] = true
+		}
+	}
+
+	r := make([]string, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r, nil
+}
+
+// Match returns the sorted list of files in fsys matched by p. See
+// Directive.Match for details.
+func (p Pattern) Match(fsys fs.FS) ([]string, error) {
+	if err := checkPattern(p.Path()); err != nil {
+		return nil, fmt.Errorf("pattern %s: %v", p.Value, err)
+	}
+
+	matches, err := fs.Glob(fsys, p.Path())
+	if err != nil {
+		return nil, fmt.Errorf("pattern %s: %v", p.Value, err)
+	}
+
+	var r []string
+	for _, match := range matches {
+		info, err := fs.Stat(fsys, match)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %s: %v", p.Value, err)
+		}
+
+		switch {
+		case info.Mode().IsRegular():
+			r = append(r, match)
+		case info.IsDir():
+			n := len(r)
+			if err := fs.WalkDir(fsys, match, func(name string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if name == match {
+					return nil
+				}
+
+				if base := path.Base(name); !p.All() && (base[0] == '.' || base[0] == '_') {
+					if d.IsDir() {
+						return fs.SkipDir
+					}
+
+					return nil
+				}
+
+				if d.IsDir() {
+					if _, err := fs.Stat(fsys, path.Join(name, "go.mod")); err == nil {
+						return fs.SkipDir
+					}
+
+					return nil
+				}
+
+				if d.Type().IsRegular() {
+					r = append(r, name)
+				}
+				return nil
+			}); err != nil {
+				return nil, fmt.Errorf("pattern %s: %v", p.Value, err)
+			}
+
+			if len(r) == n {
+				return nil, fmt.Errorf("pattern %s: cannot embed directory %s: contains no embeddable files", p.Value, match)
+			}
+		default:
+			return nil, fmt.Errorf("pattern %s: cannot embed irregular file %s", p.Value, match)
+		}
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("pattern %s: no matching files found", p.Value)
+	}
+
+	sort.Strings(r)
+	return r, nil
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func dbg(s string, va ...interface{}) {
+	_, fn, fl, _ := runtime.Caller(1)
+	fmt.Printf("%s:%d: ", path.Base(fn), fl)
+	fmt.Printf(s, va...)
+	fmt.Println()
+}
+
+// ============================================================================
+
+const testMod = `// Deprecated: use example.com/other instead.
+module example.com/m
+
+go 1.22
+
+toolchain go1.22.3
+
+require example.com/a v1.0.0
+
+require (
+	// The b module.
+	example.com/b v1.2.3 // indirect
+	"example.com/c" v0.0.0-20240101000000-abcdefabcdef
+)
+
+exclude example.com/a v0.9.0
+
+replace (
+	example.com/b => ../b
+	example.com/c v0.1.0 => example.com/d v0.2.0
+)
+
+// Published by mistake.
+retract [v1.0.0, v1.0.5]
+retract v1.1.0 // Broken build.
+
+// trailing comment
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("go.mod", []byte(testMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := f.Module.Path, "example.com/m"; g != e {
+		t.Errorf("module: got %q, expected %q", g, e)
+	}
+	if g, e := f.Module.Deprecated, "use example.com/other instead."; g != e {
+		t.Errorf("deprecated: got %q, expected %q", g, e)
+	}
+	if g, e := f.Go.Version, "1.22"; g != e {
+		t.Errorf("go: got %q, expected %q", g, e)
+	}
+	if g, e := f.Toolchain.Name, "go1.22.3"; g != e {
+		t.Errorf("toolchain: got %q, expected %q", g, e)
+	}
+	if g, e := len(f.Require), 3; g != e {
+		t.Fatalf("require: got %d, expected %d", g, e)
+	}
+	if r := f.Require[1]; r.Path != "example.com/b" || !r.Indirect || r.Pos != (Pos{12, 2}) {
+		t.Errorf("require[1]: %+v", r)
+	}
+	if r := f.Require[2]; r.Path != "example.com/c" || r.Indirect {
+		t.Errorf("require[2]: %+v", r)
+	}
+	if g, e := f.Replace[0].New.Path, "../b"; g != e {
+		t.Errorf("replace[0]: got %q, expected %q", g, e)
+	}
+	if g, e := f.Replace[1].Old.String()+" "+f.Replace[1].New.String(), "example.com/c@v0.1.0 example.com/d@v0.2.0"; g != e {
+		t.Errorf("replace[1]: got %q, expected %q", g, e)
+	}
+	if r := f.Retract[0]; r.Low != "v1.0.0" || r.High != "v1.0.5" || r.Rationale != "Published by mistake." {
+		t.Errorf("retract[0]: %+v", r)
+	}
+	if r := f.Retract[1]; r.Low != "v1.1.0" || r.High != "v1.1.0" || r.Rationale != "Broken build." {
+		t.Errorf("retract[1]: %+v", r)
+	}
+	if l, ok := f.Syntax[len(f.Syntax)-1].(*Line); !ok || len(l.Token) != 0 || l.Before[0].Text != "// trailing comment" {
+		t.Errorf("trailing comment: %#v", f.Syntax[len(f.Syntax)-1])
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	f, err := Parse("go.mod", []byte(`module example.com/m
+
+go 1.25
+
+toolchain default
+
+godebug default=go1.21
+
+godebug (
+	panicnil=1
+	asynctimerchan=0
+)
+
+tool example.com/a/cmd/a
+
+tool (
+	example.com/b
+)
+
+ignore ./node_modules
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := f.Toolchain.Name, "default"; g != e {
+		t.Errorf("toolchain: got %q, expected %q", g, e)
+	}
+	var a []string
+	for _, v := range f.Godebug {
+		a = append(a, fmt.Sprintf("%v %s=%s", v.Pos, v.Key, v.Value))
+	}
+	for _, v := range f.Tool {
+		a = append(a, fmt.Sprintf("%v tool %s", v.Pos, v.Path))
+	}
+	for _, v := range f.Ignore {
+		a = append(a, fmt.Sprintf("%v ignore %s", v.Pos, v.Path))
+	}
+	if g, e := strings.Join(a, ", "), "7:1 default=go1.21, 10:2 panicnil=1, 11:2 asynctimerchan=0, 14:1 tool example.com/a/cmd/a, 17:2 tool example.com/b, 20:1 ignore ./node_modules"; g != e {
+		t.Errorf("\ngot      %s\nexpected %s", g, e)
+	}
+
+	w, err := ParseWork("go.work", []byte("go 1.23\n\ngodebug (\n\tpanicnil=1\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := fmt.Sprintf("%s=%s", w.Godebug[0].Key, w.Godebug[0].Value), "panicnil=1"; g != e {
+		t.Errorf("go.work godebug: got %q, expected %q", g, e)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for i, test := range []struct {
+		src, err string
+	}{
+		{"module a\nmodule b\n", "go.mod:2:1: repeated module statement"},
+		{"go 1.2.3.4\n", "go.mod:1:1: invalid go version '1.2.3.4': must match format 1.23.0"},
+		{"toolchain 1.22.0\n", "go.mod:1:1: invalid toolchain name '1.22.0': must be of the form go1.23.0"},
+		{"go go1.22\n", "go.mod:1:1: invalid go version 'go1.22': must match format 1.23.0"},
+		{"go 1\n", "go.mod:1:1: invalid go version '1': must match format 1.23.0"},
+		{"require a v1\n", `go.mod:1:1: invalid version "v1": must be of the form v1.2.3`},
+		{"require (\n\ta v1.0.0\n", "go.mod:3:1: unexpected EOF, expected ')'"},
+		{"replace a => b\n", "go.mod:1:1: replacement module without version must be directory path (rooted or starting with ./ or ../)"},
+		{"frob a\n", "go.mod:1:1: unknown directive: frob"},
+		{"godebug panicnil\n", "go.mod:1:1: usage: godebug key=value"},
+		{"tool a b\n", "go.mod:1:1: usage: tool module/path/to/tool"},
+		{"module \"a\n", "go.mod:1:8: unterminated quoted string"},
+		{"module m\x00\n", "go.mod:1:9: unexpected character '\\x00'"},
+	} {
+		_, err := Parse("go.mod", []byte(test.src))
+		if err == nil {
+			t.Errorf("%d: unexpected success", i)
+			continue
+		}
+
+		if g, e := err.Error(), test.err; !strings.Contains(g, e) {
+			t.Errorf("%d: got %q, expected %q", i, g, e)
+		}
+	}
+}
+
+func TestErrorsUnwrap(t *testing.T) {
+	_, err := Parse("go.mod", []byte("module a\nmodule b\nfrob\n"))
+	var list interface{ Unwrap() []error }
+	if !errors.As(err, &list) {
+		t.Fatalf("%T does not implement Unwrap() []error", err)
+	}
+
+	errs := list.Unwrap()
+	if g, e := len(errs), 2; g != e {
+		t.Fatalf("got %d errors, expected %d", g, e)
+	}
+
+	if !errors.Is(err, errs[1]) {
+		t.Error("errors.Is failed")
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	src := []byte("module a\nmodule b\nfrob\nrequire a\n")
+	_, err := Parse("go.mod", src)
+	var e *Error
+	if !errors.As(err, &e) || e.Fname != "go.mod" || e.Pos != (Pos{2, 1}) || e.Msg != "repeated module statement" {
+		t.Fatalf("got %#v", e)
+	}
+
+	if g, e := len(err.(ErrorList)), 3; g != e {
+		t.Fatalf("got %d errors, expected %d", g, e)
+	}
+
+	_, err = Parse("go.mod", src, MaxErrors(2))
+	if g, e := err.Error(), "go.mod:2:1: repeated module statement\ngo.mod:3:1: unknown directive: frob"; g != e {
+		t.Errorf("got %q, expected %q", g, e)
+	}
+}
+
+func TestParseWork(t *testing.T) {
+	f, err := ParseWork("go.work", []byte(`go 1.22
+
+use (
+	./a
+	"./b"
+)
+
+use ../c
+
+replace example.com/a v1.0.0 => ./a
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := f.Go.Version, "1.22"; g != e {
+		t.Errorf("go: got %q, expected %q", g, e)
+	}
+	var a []string
+	for _, v := range f.Use {
+		a = append(a, fmt.Sprintf("%v %s", v.Pos, v.Path))
+	}
+	if g, e := strings.Join(a, ", "), "4:2 ./a, 5:2 ./b, 8:1 ../c"; g != e {
+		t.Errorf("use: got %q, expected %q", g, e)
+	}
+	if g, e := f.Replace[0].Old.String(), "example.com/a@v1.0.0"; g != e {
+		t.Errorf("replace: got %q, expected %q", g, e)
+	}
+
+	if _, err := ParseWork("go.work", []byte("module example.com/m\n")); err == nil {
+		t.Error("unexpected success")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	f, err := Parse("go.mod", []byte(testMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := string(f.Format()), testMod; g != e {
+		t.Errorf("round trip\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+
+	const parens = "require ( // a\n\tx.com/a v1.0.0\n) // b\n"
+	if f, err = Parse("go.mod", []byte(parens)); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := string(f.Format()), parens; g != e {
+		t.Errorf("round trip\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+
+	f, err = Parse("go.mod", []byte(`module   example.com/m
+
+
+require(   // deps
+    example.com/a   v1.0.0
+
+    // last
+)
+retract [ v1.0.0 ,v1.0.1 ]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := string(f.Format()), `module example.com/m
+
+require ( // deps
+	example.com/a v1.0.0
+
+	// last
+)
+retract [v1.0.0, v1.0.1]
+`; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+}
+
+func ExampleWorkFile_Format() {
+	f, err := ParseWork("go.work", []byte(`go 1.22
+use   ./a  // main module
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	f.Syntax = append(f.Syntax, &Line{Token: []string{"use", "./b"}})
+	fmt.Printf("%s", f.Format())
+	// Output:
+	// go 1.22
+	// use ./a // main module
+	// use ./b
+}
+
+func ExampleParse() {
+	f, err := Parse("go.mod", []byte(`module example.com/m
+
+go 1.22
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.2.3 // indirect
+)
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(f.Module.Path, f.Go.Version)
+	for _, v := range f.Require {
+		fmt.Printf("%v: %v indirect=%v\n", v.Pos, v.ModVersion, v.Indirect)
+	}
+	// Output:
+	// example.com/m 1.22
+	// 6:2: example.com/a@v1.0.0 indirect=false
+	// 7:2: example.com/b@v1.2.3 indirect=true
+}
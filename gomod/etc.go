@@ -0,0 +1,242 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for go.mod[0] and go.work[8] files.
+//
+// The result of Parse has two layers. The syntax layer (File.Syntax) records
+// every directive line and block as written, together with any attached
+// comments. The typed layer (File.Module, File.Require, ...) interprets the
+// syntax layer according to the go.mod reference[0].
+//
+// Format prints the syntax layer back in canonical form, preserving comments
+// and the grouping of directives, so tools can edit the syntax layer and
+// write the result back.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://go.dev/ref/mod#go-mod-file
+//	[1]: https://go.dev/ref/mod#go-mod-file-module
+//	[2]: https://go.dev/ref/mod#go-mod-file-go
+//	[3]: https://go.dev/ref/mod#go-mod-file-toolchain
+//	[4]: https://go.dev/ref/mod#go-mod-file-require
+//	[5]: https://go.dev/ref/mod#go-mod-file-exclude
+//	[6]: https://go.dev/ref/mod#go-mod-file-replace
+//	[7]: https://go.dev/ref/mod#go-mod-file-retract
+//	[8]: https://go.dev/ref/mod#go-work-file
+//	[9]: https://go.dev/ref/mod#go-work-file-use
+//	[10]: https://go.dev/ref/mod#go-mod-file-godebug
+//	[11]: https://go.dev/ref/mod#go-mod-file-tool
+//	[12]: https://go.dev/ref/mod#go-mod-file-ignore
+package parser
+
+import (
+	"fmt"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col }
+
+// Comment is a single // comment. Text includes the leading slashes.
+type Comment struct {
+	Pos
+	Text string
+}
+
+// Comments collects the comments attached to a syntax element.
+type Comments struct {
+	Before []Comment // Whole line comments immediately preceding the element.
+	Suffix []Comment // Comment following the element on the same line.
+}
+
+// Stmt is a top level syntax element, either a *Line or a *Block.
+type Stmt interface {
+	isStmt()
+}
+
+// Line is a single directive line, either on its own or within a Block. Token
+// holds the words of the line as written, including any quotes. A Line
+// without tokens holds a group of comments not attached to any directive.
+type Line struct {
+	Pos
+	Comments
+	Token []string
+	Blank bool // The line is preceded by an empty line.
+}
+
+func (*Line) isStmt() {}
+
+// Block is a factored directive, for example
+//
+//	require (
+//		example.com/a v1.0.0
+//		example.com/b v1.2.0
+//	)
+//
+// Token holds the words before the opening parenthesis, typically only the
+// directive verb.
+type Block struct {
+	Pos
+	Comments
+	Token  []string
+	Lines  []*Line
+	RParen Comments // RParen.Suffix is the comment following the closing parenthesis.
+	Blank  bool     // The block is preceded by an empty line.
+}
+
+func (*Block) isStmt() {}
+
+// ModVersion is a module path and an optional version.
+type ModVersion struct {
+	Path    string
+	Version string
+}
+
+// String implements fmt.Stringer.
+func (m ModVersion) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+
+	return m.Path + "@" + m.Version
+}
+
+// Module describes a parsed module directive[1].
+type Module struct {
+	Pos
+	Path       string
+	Deprecated string // Deprecated is the message of a "Deprecated:" comment, if any.
+	Syntax     *Line
+}
+
+// Go describes a parsed go directive[2].
+type Go struct {
+	Pos
+	Version string
+	Syntax  *Line
+}
+
+// Toolchain describes a parsed toolchain directive[3].
+type Toolchain struct {
+	Pos
+	Name   string // Name is a toolchain name, like go1.23.0, or "default".
+	Syntax *Line
+}
+
+// Godebug describes a single parsed godebug directive[10].
+type Godebug struct {
+	Pos
+	Key    string
+	Value  string
+	Syntax *Line
+}
+
+// Tool describes a single parsed tool directive[11].
+type Tool struct {
+	Pos
+	Path   string // Path is the package path of the tool.
+	Syntax *Line
+}
+
+// Ignore describes a single parsed ignore directive[12].
+type Ignore struct {
+	Pos
+	Path   string // Path is the directory to ignore.
+	Syntax *Line
+}
+
+// Require describes a single parsed module requirement[4].
+type Require struct {
+	Pos
+	ModVersion
+	Indirect bool // Indirect reports the presence of an "// indirect" comment.
+	Syntax   *Line
+}
+
+// Exclude describes a single parsed exclude directive[5].
+type Exclude struct {
+	Pos
+	ModVersion
+	Syntax *Line
+}
+
+// Replace describes a single parsed replace directive[6]. Old.Version is
+// empty if the replacement applies to all versions of Old.Path. New.Version is
+// empty if New.Path is a file system path.
+type Replace struct {
+	Pos
+	Old    ModVersion
+	New    ModVersion
+	Syntax *Line
+}
+
+// Retract describes a single parsed retract directive[7]. Low and High are
+// equal if a single version is retracted.
+type Retract struct {
+	Pos
+	Low       string
+	High      string
+	Rationale string // Rationale is the text of the attached comments, if any.
+	Syntax    *Line
+}
+
+// File is the AST root entity.
+type File struct {
+	Syntax []Stmt
+
+	Module    *Module
+	Go        *Go
+	Toolchain *Toolchain
+	Godebug   []*Godebug
+	Require   []*Require
+	Exclude   []*Exclude
+	Replace   []*Replace
+	Retract   []*Retract
+	Tool      []*Tool
+	Ignore    []*Ignore
+}
+
+// Parse parses src as a single go.mod source file fname and returns the
+// corresponding AST. If the source couldn't be parsed, the returned AST is
+// nil and the error is an ErrorList indicating the specific failures.
+func Parse(fname string, src []byte, opts ...Option) (*File, error) {
+	p := newParser(fname, src, opts)
+	stmts := p.file()
+	if err := p.errs.Err(); err != nil {
+		return nil, err
+	}
+
+	f := &File{Syntax: stmts}
+	p.modFile(f)
+	if err := p.errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
+
+// Option configures a parse.
+type Option = errlist.Option
+
+// MaxErrors returns an Option that stops parsing once n errors are collected.
+// Zero, the default, means no limit.
+func MaxErrors(n int) Option { return errlist.MaxErrors(n) }
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"bytes"
+)
+
+// Format returns the canonical text of the syntax layer of f.
+func (f *File) Format() []byte { return Format(f.Syntax) }
+
+// Format returns the canonical text of the syntax layer of f.
+func (f *WorkFile) Format() []byte { return Format(f.Syntax) }
+
+// Format returns the canonical text of a go.mod or go.work syntax layer.
+// Blocks are indented using tabs and runs of empty lines are collapsed to a
+// single one. Formatting the result of parsing canonical text reproduces that
+// text.
+func Format(syntax []Stmt) []byte {
+	var buf bytes.Buffer
+	for _, s := range syntax {
+		switch x := s.(type) {
+		case *Line:
+			formatLine(&buf, x, "")
+		case *Block:
+			if x.Blank {
+				buf.WriteByte('\n')
+			}
+			formatComments(&buf, x.Before, "")
+			formatTokens(&buf, x.Token)
+			buf.WriteString(" (")
+			formatSuffix(&buf, x.Suffix)
+			buf.WriteByte('\n')
+			for _, l := range x.Lines {
+				formatLine(&buf, l, "\t")
+			}
+			buf.WriteByte(')')
+			formatSuffix(&buf, x.RParen.Suffix)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func formatLine(buf *bytes.Buffer, l *Line, indent string) {
+	if l.Blank {
+		buf.WriteByte('\n')
+	}
+	formatComments(buf, l.Before, indent)
+	if len(l.Token) == 0 {
+		return
+	}
+
+	buf.WriteString(indent)
+	formatTokens(buf, l.Token)
+	formatSuffix(buf, l.Suffix)
+	buf.WriteByte('\n')
+}
+
+func formatComments(buf *bytes.Buffer, c []Comment, indent string) {
+	for _, v := range c {
+		buf.WriteString(indent)
+		buf.WriteString(v.Text)
+		buf.WriteByte('\n')
+	}
+}
+
+func formatSuffix(buf *bytes.Buffer, c []Comment) {
+	for _, v := range c {
+		buf.WriteByte(' ')
+		buf.WriteString(v.Text)
+	}
+}
+
+// formatTokens writes a space separated list of tokens, except for the
+// brackets and comma of a version interval, which are written as
+// [v1.0.0, v1.1.0].
+func formatTokens(buf *bytes.Buffer, a []string) {
+	for i, v := range a {
+		if i != 0 && v != "," && v != "]" && a[i-1] != "[" {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(v)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"unicode/utf8"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+type tokenKind int
+
+const (
+	tEOF     tokenKind = iota
+	tNewline           // \n
+	tComment           // // ...
+	tWord              // identifier, path, version, ...
+	tString            // "..." or `...`
+	tLParen            // (
+	tRParen            // )
+	tLBrack            // [
+	tRBrack            // ]
+	tComma             // ,
+	tArrow             // =>
+	tIllegal
+)
+
+type token struct {
+	Pos
+	kind tokenKind
+	text string
+}
+
+// lexer tokenizes go.mod and go.work source text. Both file formats share the
+// same lexical structure.
+type lexer struct {
+	errs *errlist.Errors[Pos]
+	line int
+	col  int
+	off  int
+	src  []byte
+}
+
+func newLexer(fname string, src []byte, opts []errlist.Option) *lexer {
+	return &lexer{errs: errlist.New[Pos](fname, opts), src: src, line: 1, col: 1}
+}
+
+func (l *lexer) errorf(pos Pos, s string, va ...interface{}) {
+	l.errs.Errorf(pos, s, va...)
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.off+n < len(l.src) {
+		return l.src[l.off+n]
+	}
+
+	return 0
+}
+
+func (l *lexer) next() {
+	if l.off >= len(l.src) {
+		return
+	}
+
+	r, n := utf8.DecodeRune(l.src[l.off:])
+	l.off += n
+	switch r {
+	case '\n':
+		l.line++
+		l.col = 1
+	default:
+		l.col++
+	}
+}
+
+func isWordEnd(c byte) bool {
+	switch c {
+	case 0, ' ', '\t', '\r', '\n', '(', ')', '[', ']', ',', '"', '`':
+		return true
+	}
+
+	return false
+}
+
+// scan returns the next token.
+func (l *lexer) scan() (t token) {
+	for l.off < len(l.src) {
+		switch l.src[l.off] {
+		case ' ', '\t', '\r':
+			l.next()
+			continue
+		}
+		break
+	}
+
+	t.Pos = Pos{l.line, l.col}
+	if l.off >= len(l.src) {
+		return t
+	}
+
+	off0 := l.off
+	c := l.src[l.off]
+	switch {
+	case c == '\n':
+		l.next()
+		t.kind = tNewline
+	case c == '/' && l.peek(1) == '/':
+		for l.off < len(l.src) && l.src[l.off] != '\n' {
+			l.next()
+		}
+		t.kind = tComment
+	case c == '=' && l.peek(1) == '>':
+		l.next()
+		l.next()
+		t.kind = tArrow
+	case c == '(':
+		l.next()
+		t.kind = tLParen
+	case c == ')':
+		l.next()
+		t.kind = tRParen
+	case c == '[':
+		l.next()
+		t.kind = tLBrack
+	case c == ']':
+		l.next()
+		t.kind = tRBrack
+	case c == ',':
+		l.next()
+		t.kind = tComma
+	case c == '"':
+		l.next()
+		for {
+			if l.off >= len(l.src) || l.src[l.off] == '\n' {
+				l.errorf(t.Pos, "unterminated quoted string")
+				t.kind = tIllegal
+				break
+			}
+
+			c := l.src[l.off]
+			l.next()
+			if c == '\\' {
+				l.next()
+				continue
+			}
+
+			if c == '"' {
+				t.kind = tString
+				break
+			}
+		}
+	case c == '`':
+		l.next()
+		for {
+			if l.off >= len(l.src) {
+				l.errorf(t.Pos, "unterminated raw string")
+				t.kind = tIllegal
+				break
+			}
+
+			c := l.src[l.off]
+			l.next()
+			if c == '`' {
+				t.kind = tString
+				break
+			}
+		}
+	default:
+		for !isWordEnd(l.peek(0)) {
+			if l.peek(0) == '/' && l.peek(1) == '/' || l.peek(0) == '=' && l.peek(1) == '>' {
+				break
+			}
+
+			l.next()
+		}
+		t.kind = tWord
+		if l.off == off0 {
+			l.next()
+			l.errorf(t.Pos, "unexpected character %q", c)
+			t.kind = tIllegal
+		}
+	}
+	t.text = string(l.src[off0:l.off])
+	return t
+}
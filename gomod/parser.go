@@ -0,0 +1,467 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	goversion "github.com/cznic/parser/goversion"
+)
+
+type parser struct {
+	*lexer
+	tok token
+}
+
+func newParser(fname string, src []byte, opts []Option) *parser {
+	p := &parser{lexer: newLexer(fname, src, opts)}
+	p.advance()
+	return p
+}
+
+func (p *parser) advance() {
+	p.tok = p.scan()
+}
+
+// file parses the syntax layer of a go.mod or go.work file.
+func (p *parser) file() (stmts []Stmt) {
+	var before []Comment
+	blank := false
+	for !p.errs.Full() {
+		switch p.tok.kind {
+		case tEOF:
+			if len(before) != 0 {
+				stmts = append(stmts, &Line{Pos: before[0].Pos, Comments: Comments{Before: before}, Blank: blank})
+			}
+			return stmts
+		case tNewline:
+			if len(before) != 0 {
+				stmts = append(stmts, &Line{Pos: before[0].Pos, Comments: Comments{Before: before}, Blank: blank})
+				before = nil
+			}
+			blank = true
+			p.advance()
+		case tComment:
+			before = append(before, Comment{p.tok.Pos, p.tok.text})
+			p.advance()
+			p.lineEnd(nil)
+		case tLParen, tRParen:
+			p.errorf(p.tok.Pos, "unexpected %q", p.tok.text)
+			p.skipLine()
+		default:
+			stmts = append(stmts, p.stmt(Comments{Before: before}, blank))
+			before, blank = nil, false
+		}
+	}
+	return stmts
+}
+
+func (p *parser) stmt(c Comments, blank bool) Stmt {
+	pos := p.tok.Pos
+	words := p.words()
+	if p.tok.kind != tLParen {
+		l := &Line{Pos: pos, Comments: c, Token: words, Blank: blank}
+		p.lineEnd(&l.Comments)
+		return l
+	}
+
+	b := &Block{Pos: pos, Comments: c, Token: words, Blank: blank}
+	p.advance()
+	if p.tok.kind == tRParen {
+		p.advance()
+		p.lineEnd(&b.RParen)
+		return b
+	}
+
+	p.lineEnd(&b.Comments)
+	b.Lines = p.blockLines()
+	if p.tok.kind == tRParen {
+		p.advance()
+		p.lineEnd(&b.RParen)
+	}
+	return b
+}
+
+func (p *parser) blockLines() (lines []*Line) {
+	var before []Comment
+	blank := false
+	for {
+		switch p.tok.kind {
+		case tEOF, tRParen:
+			if p.tok.kind == tEOF {
+				p.errorf(p.tok.Pos, "unexpected EOF, expected ')'")
+			}
+			if len(before) != 0 {
+				lines = append(lines, &Line{Pos: before[0].Pos, Comments: Comments{Before: before}, Blank: blank})
+			}
+			return lines
+		case tNewline:
+			if len(before) != 0 {
+				lines = append(lines, &Line{Pos: before[0].Pos, Comments: Comments{Before: before}, Blank: blank})
+				before = nil
+			}
+			blank = true
+			p.advance()
+		case tComment:
+			before = append(before, Comment{p.tok.Pos, p.tok.text})
+			p.advance()
+			p.lineEnd(nil)
+		case tLParen:
+			p.errorf(p.tok.Pos, "unexpected '('")
+			p.skipLine()
+		default:
+			l := &Line{Pos: p.tok.Pos, Comments: Comments{Before: before}, Blank: blank}
+			l.Token = p.words()
+			p.lineEnd(&l.Comments)
+			lines = append(lines, l)
+			before, blank = nil, false
+		}
+	}
+}
+
+func (p *parser) words() (a []string) {
+	for {
+		switch p.tok.kind {
+		case tWord, tString, tLBrack, tRBrack, tComma, tArrow:
+			a = append(a, p.tok.text)
+		case tIllegal:
+			// Already reported by the lexer.
+		default:
+			return a
+		}
+		p.advance()
+	}
+}
+
+// lineEnd consumes an optional suffix comment and the end of the line.
+func (p *parser) lineEnd(c *Comments) {
+	if p.tok.kind == tComment && c != nil {
+		c.Suffix = append(c.Suffix, Comment{p.tok.Pos, p.tok.text})
+		p.advance()
+	}
+	switch p.tok.kind {
+	case tEOF:
+	case tNewline:
+		p.advance()
+	default:
+		p.errorf(p.tok.Pos, "unexpected %q", p.tok.text)
+		p.skipLine()
+	}
+}
+
+func (p *parser) skipLine() {
+	for p.tok.kind != tEOF && p.tok.kind != tNewline {
+		p.advance()
+	}
+	p.advance()
+}
+
+// modFile interprets the syntax layer of f as go.mod directives.
+func (p *parser) modFile(f *File) {
+	for _, s := range f.Syntax {
+		if p.errs.Full() {
+			return
+		}
+
+		switch x := s.(type) {
+		case *Line:
+			if len(x.Token) != 0 {
+				p.modLine(f, x.Token[0], x, x.Token[1:])
+			}
+		case *Block:
+			if len(x.Token) != 1 {
+				p.errorf(x.Pos, "unknown block type: %s", strings.Join(x.Token, " "))
+				break
+			}
+
+			verb := x.Token[0]
+			switch verb {
+			case "godebug", "require", "exclude", "replace", "retract", "tool", "ignore":
+				for _, l := range x.Lines {
+					if len(l.Token) != 0 {
+						p.modLine(f, verb, l, l.Token)
+					}
+				}
+			default:
+				p.errorf(x.Pos, "unknown block type: %s", verb)
+			}
+		}
+	}
+}
+
+func (p *parser) modLine(f *File, verb string, l *Line, args []string) {
+	switch verb {
+	case "module":
+		if f.Module != nil {
+			p.errorf(l.Pos, "repeated module statement")
+			return
+		}
+
+		if len(args) != 1 {
+			p.errorf(l.Pos, "usage: module module/path")
+			return
+		}
+
+		if s, ok := p.unquote(l, args[0]); ok {
+			f.Module = &Module{Pos: l.Pos, Path: s, Deprecated: deprecation(l), Syntax: l}
+		}
+	case "go":
+		if f.Go != nil {
+			p.errorf(l.Pos, "repeated go statement")
+			return
+		}
+
+		f.Go = p.goDirective(l, args)
+	case "toolchain":
+		if f.Toolchain != nil {
+			p.errorf(l.Pos, "repeated toolchain statement")
+			return
+		}
+
+		f.Toolchain = p.toolchain(l, args)
+	case "godebug":
+		if g := p.godebug(l, args); g != nil {
+			f.Godebug = append(f.Godebug, g)
+		}
+	case "require":
+		if len(args) != 2 {
+			p.errorf(l.Pos, "usage: require module/path v1.2.3")
+			return
+		}
+
+		if mv, ok := p.modVersion(l, args[0], args[1]); ok {
+			f.Require = append(f.Require, &Require{Pos: l.Pos, ModVersion: mv, Indirect: isIndirect(l), Syntax: l})
+		}
+	case "exclude":
+		if len(args) != 2 {
+			p.errorf(l.Pos, "usage: exclude module/path v1.2.3")
+			return
+		}
+
+		if mv, ok := p.modVersion(l, args[0], args[1]); ok {
+			f.Exclude = append(f.Exclude, &Exclude{Pos: l.Pos, ModVersion: mv, Syntax: l})
+		}
+	case "replace":
+		if r := p.replace(l, args); r != nil {
+			f.Replace = append(f.Replace, r)
+		}
+	case "retract":
+		var low, high string
+		switch {
+		case len(args) == 1:
+			low, high = args[0], args[0]
+		case len(args) == 5 && args[0] == "[" && args[2] == "," && args[4] == "]":
+			low, high = args[1], args[3]
+		default:
+			p.errorf(l.Pos, "usage: retract v1.2.3 or retract [v1.2.3, v1.2.4]")
+			return
+		}
+
+		lo, ok := p.version(l, low)
+		if !ok {
+			return
+		}
+
+		hi, ok := p.version(l, high)
+		if !ok {
+			return
+		}
+
+		f.Retract = append(f.Retract, &Retract{Pos: l.Pos, Low: lo, High: hi, Rationale: commentText(l), Syntax: l})
+	case "tool":
+		if len(args) != 1 {
+			p.errorf(l.Pos, "usage: tool module/path/to/tool")
+			return
+		}
+
+		if s, ok := p.unquote(l, args[0]); ok {
+			f.Tool = append(f.Tool, &Tool{Pos: l.Pos, Path: s, Syntax: l})
+		}
+	case "ignore":
+		if len(args) != 1 {
+			p.errorf(l.Pos, "usage: ignore ./local/dir")
+			return
+		}
+
+		if s, ok := p.unquote(l, args[0]); ok {
+			f.Ignore = append(f.Ignore, &Ignore{Pos: l.Pos, Path: s, Syntax: l})
+		}
+	default:
+		p.errorf(l.Pos, "unknown directive: %s", verb)
+	}
+}
+
+func (p *parser) goDirective(l *Line, args []string) *Go {
+	if len(args) != 1 {
+		p.errorf(l.Pos, "usage: go 1.23")
+		return nil
+	}
+
+	// Unlike the go command in general, go.mod requires a minor version.
+	if v, err := goversion.Parse(args[0]); err != nil || v.Minor < 0 || strings.HasPrefix(args[0], "go") {
+		p.errorf(l.Pos, "invalid go version '%s': must match format 1.23.0", args[0])
+		return nil
+	}
+
+	return &Go{Pos: l.Pos, Version: args[0], Syntax: l}
+}
+
+func (p *parser) toolchain(l *Line, args []string) *Toolchain {
+	if len(args) != 1 {
+		p.errorf(l.Pos, "usage: toolchain go1.23.0")
+		return nil
+	}
+
+	if _, err := goversion.ParseToolchain(args[0]); err != nil && args[0] != "default" {
+		p.errorf(l.Pos, "invalid toolchain name '%s': must be of the form go1.23.0", args[0])
+		return nil
+	}
+
+	return &Toolchain{Pos: l.Pos, Name: args[0], Syntax: l}
+}
+
+// godebug interprets the arguments of a godebug directive. Both go.mod and
+// go.work files use the same form.
+func (p *parser) godebug(l *Line, args []string) *Godebug {
+	if len(args) != 1 || strings.ContainsAny(args[0], "\"`',") {
+		p.errorf(l.Pos, "usage: godebug key=value")
+		return nil
+	}
+
+	key, value, ok := strings.Cut(args[0], "=")
+	if !ok || key == "" {
+		p.errorf(l.Pos, "usage: godebug key=value")
+		return nil
+	}
+
+	return &Godebug{Pos: l.Pos, Key: key, Value: value, Syntax: l}
+}
+
+// replace interprets the arguments of a replace directive. Both go.mod and
+// go.work files use the same form.
+func (p *parser) replace(l *Line, args []string) *Replace {
+	arrow := 2
+	if len(args) >= 2 && args[1] == "=>" {
+		arrow = 1
+	}
+	if len(args) < arrow+2 || len(args) > arrow+3 || args[arrow] != "=>" {
+		p.errorf(l.Pos, "usage: replace module/path [v1.2.3] => other/module v1.4 or replace module/path [v1.2.3] => ../local/directory")
+		return nil
+	}
+
+	r := &Replace{Pos: l.Pos, Syntax: l}
+	var ok bool
+	if r.Old.Path, ok = p.unquote(l, args[0]); !ok {
+		return nil
+	}
+
+	if arrow == 2 {
+		if r.Old.Version, ok = p.version(l, args[1]); !ok {
+			return nil
+		}
+	}
+
+	if r.New.Path, ok = p.unquote(l, args[arrow+1]); !ok {
+		return nil
+	}
+
+	if len(args) == arrow+2 {
+		if !isLocalPath(r.New.Path) {
+			p.errorf(l.Pos, "replacement module without version must be directory path (rooted or starting with ./ or ../)")
+			return nil
+		}
+
+		return r
+	}
+
+	if isLocalPath(r.New.Path) {
+		p.errorf(l.Pos, "replacement module directory path %q cannot have version", r.New.Path)
+		return nil
+	}
+
+	if r.New.Version, ok = p.version(l, args[arrow+2]); !ok {
+		return nil
+	}
+
+	return r
+}
+
+func (p *parser) modVersion(l *Line, path, version string) (mv ModVersion, ok bool) {
+	if mv.Path, ok = p.unquote(l, path); !ok {
+		return mv, false
+	}
+
+	mv.Version, ok = p.version(l, version)
+	return mv, ok
+}
+
+func (p *parser) unquote(l *Line, s string) (string, bool) {
+	if s == "" || s[0] != '"' && s[0] != '`' {
+		return s, true
+	}
+
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		p.errorf(l.Pos, "invalid quoted string: %s", s)
+		return "", false
+	}
+
+	return u, true
+}
+
+func (p *parser) version(l *Line, s string) (string, bool) {
+	s, ok := p.unquote(l, s)
+	if !ok {
+		return "", false
+	}
+
+	if !semverRE.MatchString(s) {
+		p.errorf(l.Pos, "invalid version %q: must be of the form v1.2.3", s)
+		return "", false
+	}
+
+	return s, true
+}
+
+var semverRE = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+func isLocalPath(s string) bool {
+	return strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "/") ||
+		s == "." || s == ".." ||
+		strings.HasPrefix(s, `.\`) || strings.HasPrefix(s, `..\`) || strings.HasPrefix(s, `\`) ||
+		len(s) >= 3 && s[1] == ':' && (s[2] == '\\' || s[2] == '/')
+}
+
+// commentText returns the text of the comments attached to l, without the
+// comment markers.
+func commentText(l *Line) string {
+	var a []string
+	for _, v := range append(l.Before[:len(l.Before):len(l.Before)], l.Suffix...) {
+		a = append(a, strings.TrimSpace(strings.TrimPrefix(v.Text, "//")))
+	}
+	return strings.Join(a, "\n")
+}
+
+// deprecation returns the message of a "Deprecated:" paragraph in the
+// comments attached to l.
+func deprecation(l *Line) string {
+	for _, v := range strings.Split(commentText(l), "\n\n") {
+		if s := strings.TrimPrefix(v, "Deprecated:"); s != v {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func isIndirect(l *Line) bool {
+	if len(l.Suffix) == 0 {
+		return false
+	}
+
+	s := strings.TrimSpace(strings.TrimPrefix(l.Suffix[0].Text, "//"))
+	return s == "indirect" || strings.HasPrefix(s, "indirect;")
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"strings"
+)
+
+// Use describes a single parsed use directive[9].
+type Use struct {
+	Pos
+	Path   string // Path is the directory of the module.
+	Syntax *Line
+}
+
+// WorkFile is the AST root entity of a go.work file[8].
+type WorkFile struct {
+	Syntax []Stmt
+
+	Go        *Go
+	Toolchain *Toolchain
+	Godebug   []*Godebug
+	Use       []*Use
+	Replace   []*Replace
+}
+
+// ParseWork parses src as a single go.work source file fname and returns the
+// corresponding AST. If the source couldn't be parsed, the returned AST is
+// nil and the error is an ErrorList indicating the specific failures.
+func ParseWork(fname string, src []byte, opts ...Option) (*WorkFile, error) {
+	p := newParser(fname, src, opts)
+	stmts := p.file()
+	if err := p.errs.Err(); err != nil {
+		return nil, err
+	}
+
+	f := &WorkFile{Syntax: stmts}
+	p.workFile(f)
+	if err := p.errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// workFile interprets the syntax layer of f as go.work directives.
+func (p *parser) workFile(f *WorkFile) {
+	for _, s := range f.Syntax {
+		if p.errs.Full() {
+			return
+		}
+
+		switch x := s.(type) {
+		case *Line:
+			if len(x.Token) != 0 {
+				p.workLine(f, x.Token[0], x, x.Token[1:])
+			}
+		case *Block:
+			if len(x.Token) != 1 {
+				p.errorf(x.Pos, "unknown block type: %s", strings.Join(x.Token, " "))
+				break
+			}
+
+			verb := x.Token[0]
+			switch verb {
+			case "godebug", "use", "replace":
+				for _, l := range x.Lines {
+					if len(l.Token) != 0 {
+						p.workLine(f, verb, l, l.Token)
+					}
+				}
+			default:
+				p.errorf(x.Pos, "unknown block type: %s", verb)
+			}
+		}
+	}
+}
+
+func (p *parser) workLine(f *WorkFile, verb string, l *Line, args []string) {
+	switch verb {
+	case "go":
+		if f.Go != nil {
+			p.errorf(l.Pos, "repeated go statement")
+			return
+		}
+
+		f.Go = p.goDirective(l, args)
+	case "toolchain":
+		if f.Toolchain != nil {
+			p.errorf(l.Pos, "repeated toolchain statement")
+			return
+		}
+
+		f.Toolchain = p.toolchain(l, args)
+	case "godebug":
+		if g := p.godebug(l, args); g != nil {
+			f.Godebug = append(f.Godebug, g)
+		}
+	case "use":
+		if len(args) != 1 {
+			p.errorf(l.Pos, "usage: use local/dir")
+			return
+		}
+
+		if s, ok := p.unquote(l, args[0]); ok {
+			f.Use = append(f.Use, &Use{Pos: l.Pos, Path: s, Syntax: l})
+		}
+	case "replace":
+		if r := p.replace(l, args); r != nil {
+			f.Replace = append(f.Replace, r)
+		}
+	default:
+		p.errorf(l.Pos, "unknown directive: %s", verb)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testSum = `github.com/google/go-cmp v0.5.8 h1:e6P7q2lk1O+qJJb4BtCQXlK8vWEO8V1ZeuEdJNOqZyg=
+github.com/google/go-cmp v0.5.8/go.mod h1:17dUlkBOakJ0+DkrSSNjCkIjxS6bF9zb3elmeNGIjoY=
+
+bou.ke/monkey v1.0.2/go.mod h1:OqickVX3tNx6t33n1xvtTtu85YN5s6cKwVug+oHMaIA=
+github.com/google/go-cmp v0.5.8/go.mod h1:17dUlkBOakJ0+DkrSSNjCkIjxS6bF9zb3elmeNGIjoY=
+`
+
+func TestParse(t *testing.T) {
+	sums, err := Parse("go.sum", []byte(testSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(sums), 4; g != e {
+		t.Fatalf("got %d records, expected %d", g, e)
+	}
+
+	if s := sums[2]; s.Pos != (Pos{4, 1}) || s.Path != "bou.ke/monkey" || s.Version != "v1.0.2" || !s.GoMod {
+		t.Errorf("%+v", s)
+	}
+
+	for i, v := range strings.Split(strings.Replace(testSum, "\n\n", "\n", 1), "\n")[:4] {
+		if g, e := sums[i].String(), v; g != e {
+			t.Errorf("%d: got %q, expected %q", i, g, e)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("go.sum", []byte(`a v1.0.0
+a 1.0.0 h1:AAAA
+a v1.0.0 h1AAAA
+a v1.0.0 h1:AAAA
+a v1.0.0 h1:!!!!
+a v1.0.0 h2:AAAA
+a v h1:AAAA
+a v1.0/go.mod h1:AAAA
+a v1.0.0/go.mo h1:AAAA
+a v1.0.0-/go.mod h1:AAAA
+`))
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	if g, e := err.Error(), `go.sum:1:1: malformed line: wrong number of fields 2
+go.sum:2:1: malformed version "1.0.0"
+go.sum:3:1: malformed hash "h1AAAA"
+go.sum:4:1: malformed hash "h1:AAAA": digest has 3 bytes, expected 32
+go.sum:5:1: malformed hash "h1:!!!!": illegal base64 data at input byte 0
+go.sum:7:1: malformed version "v"
+go.sum:8:1: malformed version "v1.0/go.mod"
+go.sum:9:1: malformed version "v1.0.0/go.mo"
+go.sum:10:1: malformed version "v1.0.0-/go.mod"`; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	sums, err := Parse("go.sum", []byte(testSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Verify("go.sum", sums); err != nil {
+		t.Fatal(err)
+	}
+
+	sums[3].Hash = sums[2].Hash
+	if g, e := fmt.Sprint(Verify("go.sum", sums)), "go.sum:5:1: conflicting hash for github.com/google/go-cmp v0.5.8/go.mod, previous hash at 2:1"; g != e {
+		t.Errorf("got %q, expected %q", g, e)
+	}
+}
+
+func TestVerifyGoMod(t *testing.T) {
+	sums, err := Parse("go.sum", []byte(testSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifyGoMod(sums[2], []byte("module bou.ke/monkey\n\ngo 1.13\n")) {
+		t.Error("go.mod hash mismatch")
+	}
+
+	if VerifyGoMod(sums[2], []byte("module bou.ke/monkey\n")) {
+		t.Error("unexpected go.mod hash match")
+	}
+}
+
+func ExampleDuplicates() {
+	sums, err := Parse("go.sum", []byte(testSum))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, g := range Duplicates(sums) {
+		for _, v := range g {
+			fmt.Printf("%v: %s %s\n", v.Pos, v.Path, v.Version)
+		}
+	}
+	// Output:
+	// 2:1: github.com/google/go-cmp v0.5.8
+	// 5:1: github.com/google/go-cmp v0.5.8
+}
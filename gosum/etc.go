@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for go.sum[0] files.
+//
+// Every non empty line of a go.sum file has the form
+//
+//	module version[/go.mod] hash
+//
+// where hash is an algorithm name, a colon and a base64 encoded digest. The
+// only algorithm in use is h1[1].
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://go.dev/ref/mod#go-sum-files
+//	[1]: https://pkg.go.dev/golang.org/x/mod/sumdb/dirhash#Hash1
+package parser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col }
+
+// Sum describes a single parsed go.sum line.
+type Sum struct {
+	Pos
+	Path    string
+	Version string // Version does not include the /go.mod suffix.
+	GoMod   bool   // GoMod reports whether the hash is of the go.mod file only.
+	Hash    string // Hash is the algorithm and digest, for example "h1:...".
+}
+
+// String implements fmt.Stringer. It returns the go.sum line of s.
+func (s *Sum) String() string {
+	v := s.Version
+	if s.GoMod {
+		v += "/go.mod"
+	}
+	return fmt.Sprintf("%s %s %s", s.Path, v, s.Hash)
+}
+
+// Parse parses src as a single go.sum source file fname and returns the
+// corresponding records. If the source couldn't be parsed, the returned
+// records are nil and the error is an ErrorList indicating the malformed
+// lines.
+func Parse(fname string, src []byte, opts ...Option) (sums []*Sum, err error) {
+	errs := errlist.New[Pos](fname, opts)
+	for i, line := range bytes.Split(src, []byte("\n")) {
+		if errs.Full() {
+			break
+		}
+
+		pos := Pos{i + 1, 1}
+		f := strings.Fields(string(line))
+		if len(f) == 0 {
+			continue
+		}
+
+		if len(f) != 3 {
+			errs.Errorf(pos, "malformed line: wrong number of fields %d", len(f))
+			continue
+		}
+
+		s := &Sum{Pos: pos, Path: f[0], Version: f[1], Hash: f[2]}
+		if v := strings.TrimSuffix(s.Version, "/go.mod"); v != s.Version {
+			s.Version, s.GoMod = v, true
+		}
+		if !semverRE.MatchString(s.Version) {
+			errs.Errorf(pos, "malformed version %q", f[1])
+			continue
+		}
+
+		if err := checkHash(s.Hash); err != nil {
+			errs.Errorf(pos, "%v", err)
+			continue
+		}
+
+		sums = append(sums, s)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return sums, nil
+}
+
+// semverRE matches a canonical semantic version with a "v" prefix.
+var semverRE = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+func checkHash(h string) error {
+	i := strings.IndexByte(h, ':')
+	if i <= 0 {
+		return fmt.Errorf("malformed hash %q", h)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(h[i+1:])
+	if err != nil {
+		return fmt.Errorf("malformed hash %q: %v", h, err)
+	}
+
+	if h[:i] == "h1" && len(b) != sha256.Size {
+		return fmt.Errorf("malformed hash %q: digest has %d bytes, expected %d", h, len(b), sha256.Size)
+	}
+
+	return nil
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
+
+// Option configures a parse.
+type Option = errlist.Option
+
+// MaxErrors returns an Option that stops parsing once n errors are collected.
+// Zero, the default, means no limit.
+func MaxErrors(n int) Option { return errlist.MaxErrors(n) }
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+type key struct {
+	path    string
+	version string
+	gomod   bool
+}
+
+// Duplicates returns the groups of records in sums sharing the same module
+// path, version and go.mod marker, in order of their first appearance. Every
+// group has at least two members.
+func Duplicates(sums []*Sum) [][]*Sum {
+	m := map[key][]*Sum{}
+	var keys []key
+	for _, v := range sums {
+		k := key{v.Path, v.Version, v.GoMod}
+		if _, ok := m[k]; !ok {
+			keys = append(keys, k)
+		}
+		m[k] = append(m[k], v)
+	}
+
+	var r [][]*Sum
+	for _, k := range keys {
+		if g := m[k]; len(g) > 1 {
+			r = append(r, g)
+		}
+	}
+	return r
+}
+
+// Verify checks that duplicate records in sums agree on their hash. The
+// returned error, if any, is an ErrorList reporting every conflicting record
+// together with the position of the first record it conflicts with.
+func Verify(fname string, sums []*Sum) error {
+	errs := errlist.New[Pos](fname, nil)
+	for _, g := range Duplicates(sums) {
+		version := g[0].Version
+		if g[0].GoMod {
+			version += "/go.mod"
+		}
+		for _, v := range g[1:] {
+			if v.Hash != g[0].Hash {
+				errs.Errorf(v.Pos, "conflicting hash for %s %s, previous hash at %v", v.Path, version, g[0].Pos)
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// HashGoMod returns the h1 hash of the go.mod file content data, as recorded
+// in the /go.mod lines of go.sum.
+func HashGoMod(data []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%x  %s\n", sha256.Sum256(data), "go.mod")
+	return "h1:" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// VerifyGoMod reports whether s is a go.mod record matching the go.mod file
+// content data.
+func VerifyGoMod(s *Sum, data []byte) bool {
+	return s.GoMod && s.Hash == HashGoMod(data)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for i, test := range []struct {
+		s  string
+		v  Version
+		ok bool
+	}{
+		{"1", Version{1, -1, -1, Release, 0}, true},
+		{"1.21", Version{1, 21, -1, Release, 0}, true},
+		{"go1.21.3", Version{1, 21, 3, Release, 0}, true},
+		{"1.23rc1", Version{1, 23, -1, RC, 1}, true},
+		{"go1.18beta2", Version{1, 18, -1, Beta, 2}, true},
+		{"1.21alpha1", Version{1, 21, -1, Alpha, 1}, true},
+		{"", Version{}, false},
+		{"go", Version{}, false},
+		{"1.", Version{}, false},
+		{"1.21.", Version{}, false},
+		{"1.021", Version{}, false},
+		{"1.21.3.4", Version{}, false},
+		{"1.21rc", Version{}, false},
+		{"1.21rc0", Version{}, false},
+		{"1.21.0rc1", Version{}, false},
+		{"1.21gamma1", Version{}, false},
+		{"v1.21", Version{}, false},
+	} {
+		v, err := Parse(test.s)
+		if g, e := err == nil, test.ok; g != e {
+			t.Errorf("%d: %q: got ok %v, expected %v (%v)", i, test.s, g, e, err)
+			continue
+		}
+
+		if err == nil && v != test.v {
+			t.Errorf("%d: %q: got %+v, expected %+v", i, test.s, v, test.v)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := []string{"1.21.0", "1.3", "1.21rc2", "1.21", "go1.21.10", "1.20.5", "1.21beta1", "1.21.2", "1"}
+	sort.Slice(a, func(i, j int) bool { return Compare(a[i], a[j]) < 0 })
+	if g, e := fmt.Sprint(a), "[1 1.3 1.20.5 1.21 1.21beta1 1.21rc2 1.21.0 1.21.2 go1.21.10]"; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+
+	if Compare("go1.21.0", "1.21.0") != 0 {
+		t.Error("prefix affects comparison")
+	}
+	if Compare("x", "1") >= 0 || Compare("1", "x") <= 0 || Compare("x", "y") != 0 {
+		t.Error("invalid versions")
+	}
+}
+
+func TestParseToolchain(t *testing.T) {
+	for i, test := range []struct {
+		s, v, suffix, err string
+	}{
+		{"go1.22.0", "1.22.0", "", ""},
+		{"toolchain go1.23rc1", "1.23rc1", "", ""},
+		{"go1.21.3-bigcorp", "1.21.3", "bigcorp", ""},
+		{"1.21.3", "", "", `invalid toolchain name "1.21.3"`},
+		{"go1.21.3-", "", "", `invalid toolchain name "go1.21.3-"`},
+		{"default", "", "", `invalid toolchain name "default"`},
+	} {
+		tc, err := ParseToolchain(test.s)
+		if g, e := fmt.Sprint(err), test.err; test.err != "" || err != nil {
+			if g != e {
+				t.Errorf("%d: got error %q, expected %q", i, g, e)
+			}
+			continue
+		}
+
+		if tc.Version.String() != test.v || tc.Suffix != test.suffix {
+			t.Errorf("%d: got %+v", i, tc)
+		}
+	}
+}
+
+func ExampleVersion_Lang() {
+	v, err := Parse("go1.22.3")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(v, v.Lang())
+	// Output:
+	// 1.22.3 1.22
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for Go version strings[0], as used by
+// the go and toolchain directives of go.mod and go.work files.
+//
+// A Go version has the form 1, 1.N, 1.N.P or 1.NkindM, where kind is one of
+// alpha, beta or rc. Versions order as described by Compare; in particular the
+// language version 1.21 precedes the release candidate 1.21rc1, which
+// precedes the release 1.21.0. A version may be written with a "go" prefix,
+// for example go1.21.3. A toolchain name is a prefixed version optionally
+// followed by a '-' and a custom suffix, for example go1.21.3-custom.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://go.dev/doc/toolchain#version
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a prerelease version.
+type Kind int
+
+// Values of type Kind, in order of precedence.
+const (
+	Release Kind = iota
+	Alpha
+	Beta
+	RC
+)
+
+var kinds = []string{
+	Release: "",
+	Alpha:   "alpha",
+	Beta:    "beta",
+	RC:      "rc",
+}
+
+// String implements fmt.Stringer.
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kinds) {
+		return kinds[k]
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Version is a parsed Go version. Minor and Patch are -1 if absent.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Kind  Kind
+	Pre   int // Pre is the prerelease number, eg. 1 in 1.21rc1.
+}
+
+// String implements fmt.Stringer. It returns v without the "go" prefix.
+func (v Version) String() string {
+	s := strconv.Itoa(v.Major)
+	if v.Minor >= 0 {
+		s += "." + strconv.Itoa(v.Minor)
+	}
+	if v.Patch >= 0 {
+		s += "." + strconv.Itoa(v.Patch)
+	}
+	if v.Kind != Release {
+		s += v.Kind.String() + strconv.Itoa(v.Pre)
+	}
+	return s
+}
+
+// Lang returns the language version of v, ie. v without the patch and
+// prerelease parts.
+func (v Version) Lang() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: -1}
+}
+
+// Compare returns -1, 0 or 1 depending on whether v < w, v == w or v > w.
+func (v Version) Compare(w Version) int {
+	if c := cmpInt(v.Major, w.Major); c != 0 {
+		return c
+	}
+
+	if c := cmpInt(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+
+	if c := cmpInt(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+
+	if c := cmpInt(int(v.Kind), int(w.Kind)); c != 0 {
+		return c
+	}
+
+	return cmpInt(v.Pre, w.Pre)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Parse parses s as a Go version, with or without the "go" prefix.
+func Parse(s string) (Version, error) {
+	v, ok := parse(strings.TrimPrefix(s, "go"))
+	if !ok {
+		return Version{}, fmt.Errorf("invalid Go version %q", s)
+	}
+
+	return v, nil
+}
+
+// IsValid reports whether s is a valid Go version, with or without the "go"
+// prefix.
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether x < y, x == y or x > y.
+// Invalid versions compare less than valid ones and equal to each other.
+func Compare(x, y string) int {
+	v, errx := Parse(x)
+	w, erry := Parse(y)
+	switch {
+	case errx != nil && erry != nil:
+		return 0
+	case errx != nil:
+		return -1
+	case erry != nil:
+		return 1
+	}
+
+	return v.Compare(w)
+}
+
+func parse(s string) (v Version, ok bool) {
+	v.Minor, v.Patch = -1, -1
+	if v.Major, s, ok = number(s); !ok {
+		return v, false
+	}
+
+	if s == "" {
+		return v, true
+	}
+
+	if s[0] != '.' {
+		return v, false
+	}
+
+	if v.Minor, s, ok = number(s[1:]); !ok {
+		return v, false
+	}
+
+	if s == "" {
+		return v, true
+	}
+
+	if s[0] == '.' {
+		if v.Patch, s, ok = number(s[1:]); !ok {
+			return v, false
+		}
+
+		return v, s == ""
+	}
+
+	for k := Alpha; k <= RC; k++ {
+		if strings.HasPrefix(s, kinds[k]) {
+			v.Kind = k
+			v.Pre, s, ok = number(s[len(kinds[k]):])
+			return v, ok && s == "" && v.Pre != 0
+		}
+	}
+
+	return v, false
+}
+
+// number parses a decimal number without leading zeros at the start of s and
+// returns the remainder of s.
+func number(s string) (n int, rest string, ok bool) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 || i > 1 && s[0] == '0' || i > 9 {
+		return 0, s, false
+	}
+
+	n, _ = strconv.Atoi(s[:i])
+	return n, s[i:], true
+}
+
+// Toolchain is a parsed toolchain name.
+type Toolchain struct {
+	Version
+	Suffix string // Suffix is the custom suffix, without the leading '-'.
+}
+
+// String implements fmt.Stringer. It returns the toolchain name.
+func (t Toolchain) String() string {
+	s := "go" + t.Version.String()
+	if t.Suffix != "" {
+		s += "-" + t.Suffix
+	}
+	return s
+}
+
+// ParseToolchain parses s as a toolchain name, for example go1.21.3 or
+// go1.21.3-custom. The form of a toolchain directive, eg.
+// "toolchain go1.21.3", is accepted as well.
+func ParseToolchain(s string) (Toolchain, error) {
+	name := s
+	if f := strings.Fields(s); len(f) == 2 && f[0] == "toolchain" {
+		name = f[1]
+	}
+	if !strings.HasPrefix(name, "go") {
+		return Toolchain{}, fmt.Errorf("invalid toolchain name %q", s)
+	}
+
+	var t Toolchain
+	name = name[len("go"):]
+	if i := strings.IndexByte(name, '-'); i >= 0 {
+		name, t.Suffix = name[:i], name[i+1:]
+		if t.Suffix == "" {
+			return Toolchain{}, fmt.Errorf("invalid toolchain name %q", s)
+		}
+	}
+	var ok bool
+	if t.Version, ok = parse(name); !ok {
+		return Toolchain{}, fmt.Errorf("invalid toolchain name %q", s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type pos int
+
+func (p pos) String() string  { return fmt.Sprint(int(p)) }
+func (p pos) Less(q pos) bool { return p < q }
+
+func TestErrors(t *testing.T) {
+	e := New[pos]("f", nil)
+	if err := e.Err(); err != nil {
+		t.Fatalf("got %v, expected nil", err)
+	}
+
+	e.Errorf(3, "c")
+	e.Errorf(1, "a %d", 42)
+	e.Errorf(2, "b")
+	err := e.Err()
+	if g, e := err.Error(), "f:1: a 42\nf:2: b\nf:3: c"; g != e {
+		t.Fatalf("got %q, expected %q", g, e)
+	}
+
+	var x *Error[pos]
+	if !errors.As(err, &x) || x.Pos != 1 {
+		t.Fatalf("got %#v", x)
+	}
+
+	e = New[pos]("", []Option{MaxErrors(2)})
+	for i := 0; i < 3; i++ {
+		e.Errorf(pos(i), "x")
+	}
+	if !e.Full() || len(e.List) != 2 {
+		t.Fatalf("got %d errors, expected 2", len(e.List))
+	}
+
+	if g, e := e.Err().Error(), "0: x\n1: x"; g != e {
+		t.Fatalf("got %q, expected %q", g, e)
+	}
+}
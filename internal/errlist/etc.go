@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements the error lists shared by the parsers of this
+// repository.
+//
+// A parser declares aliases of Error and List instantiated with its position
+// type, re-exports Option and MaxErrors, and collects the errors of a single
+// parse in an Errors value.
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pos is the constraint satisfied by the position types of the parsers.
+type Pos[P any] interface {
+	fmt.Stringer
+
+	// Less reports whether the receiver precedes its argument.
+	Less(P) bool
+}
+
+// Error is a single parse error.
+type Error[P Pos[P]] struct {
+	Fname string // Empty if the source has no name.
+	Pos   P
+	Msg   string
+}
+
+// Error implements error.
+func (e *Error[P]) Error() string {
+	if e.Fname == "" {
+		return fmt.Sprintf("%v: %s", e.Pos, e.Msg)
+	}
+
+	return fmt.Sprintf("%s:%v: %s", e.Fname, e.Pos, e.Msg)
+}
+
+// List is a list of parse errors.
+type List[P Pos[P]] []*Error[P]
+
+// Sort sorts l by file name and position.
+func (l List[P]) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		a, b := l[i], l[j]
+		if a.Fname != b.Fname {
+			return a.Fname < b.Fname
+		}
+
+		return a.Pos.Less(b.Pos)
+	})
+}
+
+// Error implements error.
+func (l List[P]) Error() string {
+	a := []string{}
+	for _, v := range l {
+		a = append(a, v.Error())
+	}
+	return strings.Join(a, "\n")
+}
+
+// Unwrap returns the individual errors. It makes the list usable with
+// errors.Is and errors.As.
+func (l List[P]) Unwrap() []error {
+	r := make([]error, len(l))
+	for i, v := range l {
+		r[i] = v
+	}
+	return r
+}
+
+// Option configures a parse.
+type Option func(*config)
+
+type config struct {
+	maxErrors int
+}
+
+// MaxErrors returns an Option that stops a parse once n errors are
+// collected. Zero, the default, means no limit.
+func MaxErrors(n int) Option {
+	return func(c *config) { c.maxErrors = n }
+}
+
+// Errors collects the errors of a single parse.
+type Errors[P Pos[P]] struct {
+	List  List[P]
+	fname string
+	max   int
+}
+
+// New returns an Errors reporting errors in fname and configured by opts.
+func New[P Pos[P]](fname string, opts []Option) *Errors[P] {
+	var c config
+	for _, f := range opts {
+		f(&c)
+	}
+	return &Errors[P]{fname: fname, max: c.maxErrors}
+}
+
+// Errorf records an error at pos unless e is full.
+func (e *Errors[P]) Errorf(pos P, s string, va ...interface{}) {
+	if !e.Full() {
+		e.List = append(e.List, &Error[P]{e.fname, pos, fmt.Sprintf(s, va...)})
+	}
+}
+
+// Full reports whether e holds the maximum number of errors.
+func (e *Errors[P]) Full() bool { return e.max > 0 && len(e.List) >= e.max }
+
+// Err returns nil if e holds no errors. Otherwise it returns the sorted list
+// of errors.
+func (e *Errors[P]) Err() error {
+	if len(e.List) == 0 {
+		return nil
+	}
+
+	e.List.Sort()
+	return e.List
+}
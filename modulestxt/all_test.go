@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+const testSrc = `# example.com/a v1.0.0
+## explicit; go 1.21
+example.com/a
+example.com/a/sub
+# example.com/b v1.2.0 => ./b
+## explicit
+example.com/b
+# example.com/c v0.1.0 => example.com/d v0.2.0
+example.com/c/x
+# example.com/e => ../e
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse("modules.txt", []byte(testSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(f.Modules), 4; g != e {
+		t.Fatalf("got %d modules, expected %d", g, e)
+	}
+
+	if m := f.Modules[0]; m.ModVersion.String() != "example.com/a@v1.0.0" || !m.Explicit || m.GoVersion != "1.21" || len(m.Packages) != 2 {
+		t.Errorf("%+v", m)
+	}
+	if m := f.Modules[1]; m.Replacement.String() != "./b" || !m.Explicit || m.GoVersion != "" {
+		t.Errorf("%+v", m)
+	}
+	if m := f.Modules[2]; m.Replacement.String() != "example.com/d@v0.2.0" || m.Explicit {
+		t.Errorf("%+v", m)
+	}
+	if m := f.Modules[3]; m.Version != "" || m.Replacement.String() != "../e" {
+		t.Errorf("%+v", m)
+	}
+
+	if m, dir := f.Lookup("example.com/a/sub"); m != f.Modules[0] || dir != "vendor/example.com/a/sub" {
+		t.Errorf("got %v %q", m, dir)
+	}
+	if m, dir := f.Lookup("example.com/a/other"); m != nil || dir != "" {
+		t.Errorf("got %v %q", m, dir)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("modules.txt", []byte(`example.com/x
+## explicit
+# example.com/a
+#example.com/b v1.0.0
+# example.com/c v1.0.0
+example.com/c
+example.com/c
+# example.com/e => ../e
+example.com/e
+`))
+	if g, e := fmt.Sprint(err), `modules.txt:1:1: package example.com/x outside of module
+modules.txt:2:1: annotation outside of module
+modules.txt:3:1: malformed module line: # example.com/a
+modules.txt:4:1: malformed line: #example.com/b v1.0.0
+modules.txt:7:1: package example.com/c listed more than once
+modules.txt:9:1: package example.com/e listed for replacement example.com/e without requirement`; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+}
+
+func ExampleFile_Lookup() {
+	f, err := Parse("modules.txt", []byte(testSrc))
+	if err != nil {
+		panic(err)
+	}
+
+	m, dir := f.Lookup("example.com/c/x")
+	fmt.Printf("%v: %v => %v in %s\n", m.Pos, m.ModVersion, m.Replacement, dir)
+	// Output:
+	// 8:1: example.com/c@v0.1.0 => example.com/d@v0.2.0 in vendor/example.com/c/x
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for vendor/modules.txt[0] files.
+//
+// The file lists every vendored module on a line of the form
+//
+//	# path version [=> replacement [version]]
+//
+// or, for replacements not corresponding to a requirement,
+//
+//	# path => replacement [version]
+//
+// followed by an optional annotation line, for example
+//
+//	## explicit; go 1.21
+//
+// and by the import paths of the vendored packages of the module, one per
+// line.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://go.dev/ref/mod#vendoring
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p.Line < q.Line || p.Line == q.Line && p.Col < q.Col }
+
+// ModVersion is a module path and an optional version.
+type ModVersion struct {
+	Path    string
+	Version string
+}
+
+// String implements fmt.Stringer.
+func (m ModVersion) String() string {
+	if m.Version == "" {
+		return m.Path
+	}
+
+	return m.Path + "@" + m.Version
+}
+
+// Package describes a vendored package line.
+type Package struct {
+	Pos
+	Path string // Import path.
+}
+
+// Module describes a vendored module and its packages.
+type Module struct {
+	Pos
+	ModVersion
+	Replacement *ModVersion // Replacement is nil if the module is not replaced.
+	Explicit    bool        // The module is required explicitly by the main module.
+	GoVersion   string      // The go version of the module, if annotated.
+	Packages    []*Package
+}
+
+// File is the AST root entity.
+type File struct {
+	Modules   []*Module
+	Workspace bool // The file was produced by 'go work vendor'.
+
+	packages map[string]*Module
+}
+
+// Lookup returns the module providing the vendored package with importPath
+// and the directory, relative to the directory containing the vendor
+// directory, holding its sources. The returned module is nil if the package is
+// not vendored.
+func (f *File) Lookup(importPath string) (m *Module, dir string) {
+	if m = f.packages[importPath]; m == nil {
+		return nil, ""
+	}
+
+	return m, path.Join("vendor", importPath)
+}
+
+// Parse parses src as a single vendor/modules.txt source file fname and
+// returns the corresponding AST. If the source couldn't be parsed, the
+// returned AST is nil and the error is an ErrorList indicating the specific
+// failures.
+func Parse(fname string, src []byte, opts ...Option) (*File, error) {
+	f := &File{packages: map[string]*Module{}}
+	errs := errlist.New[Pos](fname, opts)
+	errorf := errs.Errorf
+	var m *Module
+	for i, line := range strings.Split(string(src), "\n") {
+		if errs.Full() {
+			break
+		}
+
+		pos := Pos{i + 1, 1}
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case line == "":
+			// nop
+		case strings.HasPrefix(line, "## "):
+			annotations := strings.Split(line[len("## "):], ";")
+			if m == nil {
+				if len(annotations) == 1 && strings.TrimSpace(annotations[0]) == "workspace" && len(f.Modules) == 0 {
+					f.Workspace = true
+					break
+				}
+
+				errorf(pos, "annotation outside of module")
+				break
+			}
+
+			for _, v := range annotations {
+				v = strings.TrimSpace(v)
+				switch {
+				case v == "explicit":
+					m.Explicit = true
+				case strings.HasPrefix(v, "go "):
+					m.GoVersion = strings.TrimSpace(v[len("go "):])
+				}
+			}
+		case strings.HasPrefix(line, "# "):
+			m = nil
+			a := strings.Fields(line[len("# "):])
+			x := &Module{Pos: pos}
+			switch {
+			case len(a) == 2 && a[0] != "=>":
+				x.Path, x.Version = a[0], a[1]
+			case len(a) >= 3 && len(a) <= 4 && a[1] == "=>":
+				x.Path = a[0]
+				x.Replacement = &ModVersion{Path: a[2]}
+				if len(a) == 4 {
+					x.Replacement.Version = a[3]
+				}
+			case len(a) >= 4 && len(a) <= 5 && a[2] == "=>":
+				x.Path, x.Version = a[0], a[1]
+				x.Replacement = &ModVersion{Path: a[3]}
+				if len(a) == 5 {
+					x.Replacement.Version = a[4]
+				}
+			default:
+				errorf(pos, "malformed module line: %s", line)
+				continue
+			}
+
+			m = x
+			f.Modules = append(f.Modules, m)
+		case strings.HasPrefix(line, "#"):
+			errorf(pos, "malformed line: %s", line)
+		default:
+			p := &Package{pos, strings.TrimSpace(line)}
+			switch {
+			case m == nil:
+				errorf(pos, "package %s outside of module", p.Path)
+			case m.Version == "" && m.Replacement != nil:
+				errorf(pos, "package %s listed for replacement %s without requirement", p.Path, m.Path)
+			case f.packages[p.Path] != nil:
+				errorf(pos, "package %s listed more than once", p.Path)
+			default:
+				m.Packages = append(m.Packages, p)
+				f.packages[p.Path] = m
+			}
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
+
+// Option configures a parse.
+type Option = errlist.Option
+
+// MaxErrors returns an Option that stops parsing once n errors are collected.
+// Zero, the default, means no limit.
+func MaxErrors(n int) Option { return errlist.MaxErrors(n) }
@@ -199,3 +199,7 @@ func (e errList) Error() string {
 	}
 	return strings.Join(a, "\n")
 }
+
+// Unwrap returns the individual errors. It makes the list usable with
+// errors.Is and errors.As.
+func (e errList) Unwrap() []error { return e }
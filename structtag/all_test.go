@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const src = `json:"name,omitempty,string" xml:"abc"  db:""`
+	tags, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g, e := len(tags), 3; g != e {
+		t.Fatalf("got %d tags, expected %d", g, e)
+	}
+
+	j := tags.Lookup("json")
+	if j.Off != 0 || j.ValueOff != 5 || j.Name != (Option{6, "name"}) || !j.HasOption("omitempty") || !j.HasOption("string") {
+		t.Errorf("%+v", j)
+	}
+	if g, e := src[j.Options[1].Off:j.Options[1].Off+len(j.Options[1].Value)], "string"; g != e {
+		t.Errorf("got %q, expected %q", g, e)
+	}
+
+	if x := tags.Lookup("xml"); x.Off != 29 || x.Value != "abc" || x.Name.Value != "abc" {
+		t.Errorf("%+v", x)
+	}
+
+	if d := tags.Lookup("db"); d.Off != 40 || d.Value != "" || d.Name != (Option{44, ""}) || len(d.Options) != 0 {
+		t.Errorf("%+v", d)
+	}
+
+	if tags.Lookup("yaml") != nil {
+		t.Error("unexpected yaml tag")
+	}
+}
+
+func TestParseEscapes(t *testing.T) {
+	const src = `json:"a\x2cb,omit\u00e9mpty" x:"\"y\""`
+	tags, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := tags[0]
+	if g, e := fmt.Sprint(j.Name, j.Options), "{6 a} [{11 b} {13 omit\u00e9mpty}]"; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+
+	if x := tags[1]; x.Value != `"y"` || x.Name != (Option{32, `"y"`}) {
+		t.Errorf("%+v", x)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, test := range []struct {
+		src, err string
+	}{
+		{`json`, "4: bad syntax for struct tag pair"},
+		{`:"x"`, "0: bad syntax for struct tag key"},
+		{`json:x`, "5: bad syntax for struct tag value"},
+		{`json:"x`, "5: bad syntax for struct tag value"},
+		{`json:"\q"`, "5: bad syntax for struct tag value"},
+		{`a:"x"b:"y"`, "5: key:\"value\" pairs not separated by spaces"},
+		{"a:\"x\"\tb:\"y\"", "5: bad syntax for struct tag key"},
+	} {
+		_, err := Parse(test.src)
+		if g, e := fmt.Sprint(err), test.err; g != e {
+			t.Errorf("%d: got %q, expected %q", i, g, e)
+		}
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	_, err := Parse(`a:"1"b:"2"c:"3"`)
+	var e *Error
+	if !errors.As(err, &e) || e.Pos != 5 || e.Msg != "key:\"value\" pairs not separated by spaces" {
+		t.Fatalf("got %#v", e)
+	}
+
+	if g, e := len(err.(ErrorList)), 2; g != e {
+		t.Fatalf("got %d errors, expected %d", g, e)
+	}
+}
+
+func ExampleParse() {
+	tags, err := Parse(`json:"id,omitempty" db:"user_id"`)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, v := range tags {
+		fmt.Printf("%d: %v name=%q options=%v\n", v.Off, v, v.Name.Value, v.Options)
+	}
+	// Output:
+	// 0: json:"id,omitempty" name="id" options=[{9 omitempty}]
+	// 20: db:"user_id" name="user_id" options=[]
+}
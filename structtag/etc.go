@@ -0,0 +1,215 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for Go struct tags[0].
+//
+// By convention, a struct tag is a concatenation of optionally space
+// separated key:"value" pairs. Each key is a non empty string consisting of
+// non control characters other than space, quote and colon. Each value is
+// quoted using Go string literal syntax. Many packages further split the
+// value at commas into a name and a list of options, for example
+//
+//	json:"name,omitempty"
+//
+// Positions reported by this package are byte offsets within the tag, ie.
+// within the value of the Go string literal, which for a raw string literal
+// is one less than the offset within the literal itself.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://pkg.go.dev/reflect#StructTag
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	errlist "github.com/cznic/parser/internal/errlist"
+)
+
+// Pos is a byte offset within a tag.
+type Pos int
+
+func (p Pos) String() string { return strconv.Itoa(int(p)) }
+
+// Less reports whether p precedes q.
+func (p Pos) Less(q Pos) bool { return p < q }
+
+// Option is a comma separated part of a tag value.
+type Option struct {
+	Off   int // Byte offset within the tag of the text producing Value.
+	Value string
+}
+
+// Tag describes a single parsed key:"value" pair.
+type Tag struct {
+	Off      int // Byte offset of Key within the tag.
+	Key      string
+	ValueOff int    // Byte offset of the opening quote of the value.
+	Value    string // Value is unquoted.
+	Name     Option // The part of Value before the first comma.
+	Options  []Option
+}
+
+// String implements fmt.Stringer.
+func (t *Tag) String() string { return fmt.Sprintf("%s:%s", t.Key, strconv.Quote(t.Value)) }
+
+// HasOption reports whether t has the option opt.
+func (t *Tag) HasOption(opt string) bool {
+	for _, v := range t.Options {
+		if v.Value == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// Tags is the list of all key:"value" pairs of a struct tag.
+type Tags []*Tag
+
+// Lookup returns the first tag with key or nil if there's no such tag.
+func (t Tags) Lookup(key string) *Tag {
+	for _, v := range t {
+		if v.Key == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// Parse parses the struct tag src and returns the list of its key:"value"
+// pairs. If the tag couldn't be parsed, the returned Tags is nil and the
+// error is an ErrorList indicating the specific failures. Parsing stops at the
+// first failure other than a missing separator.
+func Parse(src string) (Tags, error) {
+	var r Tags
+	errs := errlist.New[Pos]("", nil)
+	off := 0
+	for off < len(src) {
+		start := off
+		for off < len(src) && src[off] == ' ' {
+			off++
+		}
+		if off == len(src) {
+			break
+		}
+
+		if off == start && off != 0 && isKeyChar(src[off]) {
+			errs.Errorf(Pos(off), "key:\"value\" pairs not separated by spaces")
+		}
+
+		t := &Tag{Off: off}
+		for off < len(src) && isKeyChar(src[off]) {
+			off++
+		}
+		t.Key = src[t.Off:off]
+		if t.Key == "" {
+			errs.Errorf(Pos(off), "bad syntax for struct tag key")
+			return nil, errs.Err()
+		}
+
+		if off >= len(src) || src[off] != ':' {
+			errs.Errorf(Pos(off), "bad syntax for struct tag pair")
+			return nil, errs.Err()
+		}
+
+		off++
+		if off >= len(src) || src[off] != '"' {
+			errs.Errorf(Pos(off), "bad syntax for struct tag value")
+			return nil, errs.Err()
+		}
+
+		t.ValueOff = off
+		off++
+		for off < len(src) && src[off] != '"' {
+			if src[off] == '\\' {
+				off++
+			}
+			off++
+		}
+		if off >= len(src) {
+			errs.Errorf(Pos(t.ValueOff), "bad syntax for struct tag value")
+			return nil, errs.Err()
+		}
+
+		off++
+		var offs []int
+		var ok bool
+		if t.Value, offs, ok = unquote(src[t.ValueOff:off], t.ValueOff); !ok {
+			errs.Errorf(Pos(t.ValueOff), "bad syntax for struct tag value")
+			return nil, errs.Err()
+		}
+
+		t.Name, t.Options = options(t.Value, offs)
+		r = append(r, t)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func isKeyChar(c byte) bool { return c > ' ' && c != ':' && c != '"' && c != 0x7f }
+
+// unquote returns the value of the Go string literal lit, which starts at
+// offset off within the tag. offs maps every byte offset within the value, and
+// the length of the value, to the offset within the tag of the literal text
+// producing it.
+func unquote(lit string, off int) (value string, offs []int, ok bool) {
+	if _, err := strconv.Unquote(lit); err != nil {
+		return "", nil, false
+	}
+
+	var b []byte
+	off++
+	for s := lit[1 : len(lit)-1]; s != ""; {
+		c, mb, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", nil, false
+		}
+
+		n := len(b)
+		switch {
+		case c < utf8.RuneSelf || !mb:
+			b = append(b, byte(c))
+		default:
+			b = utf8.AppendRune(b, c)
+		}
+		for ; n < len(b); n++ {
+			offs = append(offs, off)
+		}
+		off += len(s) - len(tail)
+		s = tail
+	}
+	return string(b), append(offs, off), true
+}
+
+// options splits value at commas. offs is the offset mapping returned by
+// unquote.
+func options(value string, offs []int) (name Option, opts []Option) {
+	off := 0
+	for i, v := range strings.Split(value, ",") {
+		o := Option{offs[off], v}
+		off += len(v) + 1
+		if i == 0 {
+			name = o
+			continue
+		}
+
+		opts = append(opts, o)
+	}
+	return name, opts
+}
+
+// Error is a single parse error.
+type Error = errlist.Error[Pos]
+
+// ErrorList is a list of parse errors. The lists returned by this package are
+// sorted by position.
+type ErrorList = errlist.List[Pos]
@@ -0,0 +1,106 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	gomod "github.com/cznic/parser/gomod"
+)
+
+const testArchive = `Comment
+line.
+-- go.mod --
+module example.com/m
+
+go 1.22
+-- a.go --
+package m
+-- empty --
+-- b/c.go --
+package c`
+
+func TestParse(t *testing.T) {
+	a := Parse([]byte(testArchive))
+
+	if g, e := string(a.Comment), "Comment\nline.\n"; g != e {
+		t.Errorf("comment: got %q, expected %q", g, e)
+	}
+
+	var s []string
+	for _, v := range a.Files {
+		s = append(s, fmt.Sprintf("%v %s %q", v.Pos, v.Name, v.Data))
+	}
+	if g, e := fmt.Sprint(s), `[3:1 go.mod "module example.com/m\n\ngo 1.22\n" 7:1 a.go "package m\n" 9:1 empty "" 10:1 b/c.go "package c"]`; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+
+	if g, e := string(a.Format()), testArchive+"\n"; g != e {
+		t.Errorf("\n---- got\n%s\n---- expected\n%s", g, e)
+	}
+
+	if a.Lookup("b/c.go") != a.Files[3] || a.Lookup("c.go") != nil {
+		t.Error("Lookup")
+	}
+}
+
+func TestParseEdgeCases(t *testing.T) {
+	for i, test := range []struct {
+		src, files string
+	}{
+		{"", "[]"},
+		{"-- --\n--  x --\n", "[x:\"\"]"},
+		{"-- a --\r\nA\r\n", "[a:\"A\\r\\n\"]"},
+		{"x -- a --\n--a--\n", "[]"},
+	} {
+		a := Parse([]byte(test.src))
+
+		var s []string
+		for _, v := range a.Files {
+			s = append(s, fmt.Sprintf("%s:%q", v.Name, v.Data))
+		}
+		if g, e := fmt.Sprint(s), test.files; g != e {
+			t.Errorf("%d: got %s, expected %s", i, g, e)
+		}
+	}
+
+}
+
+func TestDuplicates(t *testing.T) {
+	a := Parse([]byte("-- a --\n1\n-- b --\n-- a --\n2\n-- a --\n"))
+	if g, e := len(a.Files), 4; g != e {
+		t.Fatalf("got %d files, expected %d", g, e)
+	}
+
+	var s []string
+	for _, g := range a.Duplicates() {
+		for _, v := range g {
+			s = append(s, fmt.Sprintf("%v %s %q", v.Pos, v.Name, v.Data))
+		}
+	}
+	if g, e := fmt.Sprint(s), `[1:1 a "1\n" 4:1 a "2\n" 6:1 a ""]`; g != e {
+		t.Errorf("got %s, expected %s", g, e)
+	}
+
+	if a.Lookup("a") != a.Files[0] {
+		t.Error("Lookup")
+	}
+}
+
+func ExampleFile_Position() {
+	a := Parse([]byte(testArchive))
+	f := a.Lookup("go.mod")
+	m, err := gomod.Parse(f.Name, f.Data)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s:%v: go %s\n", f.Name, m.Go.Pos, m.Go.Version)
+	fmt.Printf("test.txtar:%v: go %s\n", f.Position(m.Go.Line, m.Go.Col), m.Go.Version)
+	// Output:
+	// go.mod:3:1: go 1.22
+	// test.txtar:6:1: go 1.22
+}
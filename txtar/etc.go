@@ -0,0 +1,161 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package parser implements a parser for txtar[0] archives.
+//
+// A txtar archive is zero or more comment lines followed by a sequence of
+// files. Every file starts with a marker line of the form
+//
+//	-- name --
+//
+// and its content is the following lines up to the next marker or the end of
+// the archive.
+//
+// Archives are used throughout Go tooling tests to hold several source files
+// in one. Every File records where its content starts within the archive, so
+// that a parser can process the content under the virtual file name and the
+// positions it reports can be mapped back into the archive.
+//
+// # Links
+//
+// Referenced from elsewhere.
+//
+//	[0]: https://pkg.go.dev/golang.org/x/tools/txtar
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Pos describes a position within the parsed source.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// File describes a single file of an archive.
+type File struct {
+	Pos         // Position of the marker line.
+	Name string // Name is the virtual file name.
+	Data []byte // Data is the content of the file.
+}
+
+// Position returns the archive position of line and col, both 1-based,
+// within f.Data. The position may come from any of the parsers in this
+// repository, all of which report positions as a line and a column.
+func (f *File) Position(line, col int) Pos {
+	return Pos{f.Line + line, col}
+}
+
+// Archive is the AST root entity.
+type Archive struct {
+	Comment []byte
+	Files   []*File
+}
+
+// Lookup returns the first file with name or nil if there's no such file.
+func (a *Archive) Lookup(name string) *File {
+	for _, v := range a.Files {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// Duplicates returns the groups of files of a sharing the same name, in order
+// of their first appearance. Every group has at least two members. The txtar
+// format permits duplicate names, but tools processing an archive usually
+// expect them to be unique.
+func (a *Archive) Duplicates() [][]*File {
+	m := map[string][]*File{}
+	var names []string
+	for _, v := range a.Files {
+		if _, ok := m[v.Name]; !ok {
+			names = append(names, v.Name)
+		}
+		m[v.Name] = append(m[v.Name], v)
+	}
+
+	var r [][]*File
+	for _, k := range names {
+		if g := m[k]; len(g) > 1 {
+			r = append(r, g)
+		}
+	}
+	return r
+}
+
+// Format returns the text of a. Comment and file data lacking a final newline
+// are written with one.
+func (a *Archive) Format() []byte {
+	var buf bytes.Buffer
+	buf.Write(fixNL(a.Comment))
+	for _, v := range a.Files {
+		fmt.Fprintf(&buf, "-- %s --\n", v.Name)
+		buf.Write(fixNL(v.Data))
+	}
+	return buf.Bytes()
+}
+
+func fixNL(b []byte) []byte {
+	if len(b) == 0 || b[len(b)-1] == '\n' {
+		return b
+	}
+
+	return append(b[:len(b):len(b)], '\n')
+}
+
+// Parse parses src as a txtar archive and returns the corresponding AST. Every
+// input is a valid archive, so Parse cannot fail. Files are returned in order
+// of appearance, including any files with duplicate names.
+func Parse(src []byte) *Archive {
+	a := &Archive{}
+	var f *File
+	off := 0 // Start of the current comment or file data.
+	for i, line := 0, 1; i < len(src); line++ {
+		j := bytes.IndexByte(src[i:], '\n')
+		next := len(src)
+		if j >= 0 {
+			next = i + j + 1
+		}
+		name, ok := marker(src[i:next])
+		if !ok {
+			i = next
+			continue
+		}
+
+		switch {
+		case f == nil:
+			a.Comment = src[off:i]
+		default:
+			f.Data = src[off:i]
+		}
+		f = &File{Pos: Pos{line, 1}, Name: name}
+		a.Files = append(a.Files, f)
+		i, off = next, next
+	}
+	switch {
+	case f == nil:
+		a.Comment = src
+	default:
+		f.Data = src[off:]
+	}
+	return a
+}
+
+// marker returns the file name of a marker line.
+func marker(line []byte) (string, bool) {
+	s := strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r")
+	if !strings.HasPrefix(s, "-- ") || !strings.HasSuffix(s, " --") || len(s) < len("-- x --") {
+		return "", false
+	}
+
+	name := strings.TrimSpace(s[len("-- ") : len(s)-len(" --")])
+	return name, name != ""
+}
@@ -11,7 +11,10 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"text/scanner"
+	"unicode"
 )
 
 func dbg(s string, va ...interface{}) {
@@ -712,3 +715,167 @@ Foo:
 	// . Tail: ""
 	// }
 }
+
+func TestEBNF(t *testing.T) {
+	a, err := filepath.Glob(filepath.Join("testdata", "*.y"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pth := range append(a, "parser.y") {
+		src, err := ioutil.ReadFile(pth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		spec, err := Parse(pth, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := checkEBNF(spec.EBNF()); err != nil {
+			t.Errorf("%s: %v", pth, err)
+		}
+	}
+}
+
+// checkEBNF verifies the EBNF grammar src like golang.org/x/exp/ebnf.Verify,
+// with the first production as the start production: every name must be
+// defined exactly once, every production must be reachable from the start
+// production and lexical productions may refer only to lexical productions.
+func checkEBNF(src string) error {
+	var s scanner.Scanner
+	s.Init(strings.NewReader(src))
+	s.Error = func(*scanner.Scanner, string) {}
+	refs := map[string][]string{}
+	var names []string
+	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+		name := s.TokenText()
+		if tok != scanner.Ident || s.Scan() != '=' {
+			return fmt.Errorf("%s: expected production", s.Position)
+		}
+
+		if _, ok := refs[name]; ok {
+			return fmt.Errorf("%s: %s redeclared", s.Position, name)
+		}
+
+		names = append(names, name)
+		refs[name] = []string{}
+		for tok = s.Scan(); tok != '.'; tok = s.Scan() {
+			switch tok {
+			case scanner.EOF:
+				return fmt.Errorf("%s: unexpected EOF", s.Position)
+			case scanner.Ident:
+				refs[name] = append(refs[name], s.TokenText())
+			}
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("empty grammar")
+	}
+
+	isLexical := func(name string) bool { return !unicode.IsUpper([]rune(name)[0]) }
+	reached := map[string]bool{names[0]: true}
+	for todo := []string{names[0]}; len(todo) != 0; {
+		n := todo[0]
+		todo = todo[1:]
+		for _, v := range refs[n] {
+			if _, ok := refs[v]; !ok {
+				return fmt.Errorf("missing production %s", v)
+			}
+
+			if isLexical(n) && !isLexical(v) {
+				return fmt.Errorf("reference to non-lexical production %s in %s", v, n)
+			}
+
+			if !reached[v] {
+				reached[v] = true
+				todo = append(todo, v)
+			}
+		}
+	}
+	for _, n := range names {
+		if !reached[n] {
+			return fmt.Errorf("%s is unreachable", n)
+		}
+	}
+	return nil
+}
+
+func ExampleSpec_EBNF() {
+	spec, err := Parse("expr.y", []byte(`
+
+%token NUMBER
+%start Expr
+
+%%
+
+Expr:
+	NUMBER
+|	'(' List ')'
+	{
+		$$ = $2
+	}
+
+List:
+	/* Empty */
+|	List ',' Expr
+
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(spec.EBNF())
+	// Output:
+	// Expr   = NUMBER
+	//        | "(" List ")"
+	//        .
+	// List   = [ List "," Expr ]
+	//        .
+	//
+	// NUMBER = .
+}
+
+func ExampleSpec_EBNF_names() {
+	spec, err := Parse("expr.y", []byte(`
+
+%token num
+%start expr
+
+%%
+
+expr:
+	term
+|	expr '+' term
+
+term:
+	num
+|	'(' expr ')'
+|	Term
+
+Term:
+	num '!'
+
+unused:
+	num
+
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(spec.EBNF())
+	// Output:
+	// Expr  = Term_
+	//       | Expr "+" Term_
+	//       .
+	// Term_ = num
+	//       | "(" Expr ")"
+	//       | Term
+	//       .
+	// Term  = num "!"
+	//       .
+	//
+	// num   = .
+}
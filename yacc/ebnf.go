@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EBNF returns the grammar of s in the EBNF notation of the Go language
+// specification. Actions and precedence are dropped. Rules sharing a name are
+// merged into a single production, starting with the %start rule, or the
+// first rule if there's no %start definition. Rules not reachable from the
+// start rule are omitted. EBNF has no empty sequences, so the alternatives of
+// a rule having an empty body are written as an option, [ ... ]. A rule with
+// only empty bodies is an empty production. Every name not defined by a rule,
+// ie. every token, is written as an empty production after the rules, so the
+// result is self contained.
+//
+// In EBNF a production whose name starts with a lower case letter is lexical
+// and may refer only to other lexical productions. Rule names not starting
+// with an upper case letter are therefore capitalized, or prefixed with X if
+// they don't start with a letter. Characters not valid in an identifier are
+// replaced by _. A renamed production gets as many trailing underscores as
+// needed to keep its name unique. With these changes the result passes the
+// checks of golang.org/x/exp/ebnf.Verify.
+func (s *Spec) EBNF() string {
+	var names []string
+	alts := map[string][][]string{}
+	for _, r := range s.Rules {
+		if _, ok := alts[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		seq := []string{}
+		for _, v := range r.Body {
+			switch x := v.(type) {
+			case string:
+				seq = append(seq, x)
+			case int:
+				seq = append(seq, strconv.Quote(string(rune(x))))
+			}
+		}
+		alts[r.Name] = append(alts[r.Name], seq)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+
+	for _, v := range s.Defs {
+		if v.Rword != Start || alts[v.Tag] == nil {
+			continue
+		}
+
+		for i, n := range names {
+			if n == v.Tag {
+				copy(names[1:i+1], names[:i])
+				names[0] = n
+				break
+			}
+		}
+	}
+
+	// Collect the rules reachable from the start rule and the tokens they
+	// refer to.
+	var tokens []string
+	reached := map[string]bool{names[0]: true}
+	for todo := []string{names[0]}; len(todo) != 0; {
+		n := todo[0]
+		todo = todo[1:]
+		for _, seq := range alts[n] {
+			for _, v := range seq {
+				if v[0] == '"' || reached[v] {
+					continue
+				}
+
+				reached[v] = true
+				switch {
+				case alts[v] == nil:
+					tokens = append(tokens, v)
+				default:
+					todo = append(todo, v)
+				}
+			}
+		}
+	}
+	w := 0
+	for _, n := range names {
+		if reached[n] {
+			names[w] = n
+			w++
+		}
+	}
+	names = names[:w]
+
+	ident := ebnfNames(names, tokens)
+	w = 0
+	for _, v := range ident {
+		if len(v) > w {
+			w = len(v)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, n := range names {
+		var a []string
+		empty := false
+		for _, seq := range alts[n] {
+			if len(seq) == 0 {
+				empty = true
+				continue
+			}
+
+			var b []string
+			for _, v := range seq {
+				if v[0] != '"' {
+					v = ident[v]
+				}
+				b = append(b, v)
+			}
+			a = append(a, strings.Join(b, " "))
+		}
+		if len(a) == 0 {
+			fmt.Fprintf(&buf, "%-*s = .\n", w, ident[n])
+			continue
+		}
+
+		if empty {
+			a[0] = "[ " + a[0]
+			a[len(a)-1] += " ]"
+		}
+		for i, v := range a {
+			switch i {
+			case 0:
+				fmt.Fprintf(&buf, "%-*s = %s\n", w, ident[n], v)
+			default:
+				fmt.Fprintf(&buf, "%*s | %s\n", w, "", v)
+			}
+		}
+		fmt.Fprintf(&buf, "%*s .\n", w, "")
+	}
+	if len(tokens) != 0 {
+		buf.WriteByte('\n')
+	}
+	for _, v := range tokens {
+		fmt.Fprintf(&buf, "%-*s = .\n", w, ident[v])
+	}
+	return buf.String()
+}
+
+// ebnfNames maps the names of rules and tokens to EBNF production names. See
+// Spec.EBNF for the rules.
+func ebnfNames(rules, tokens []string) map[string]string {
+	m := map[string]string{}
+	used := map[string]bool{}
+	var renamed []string
+	for i, v := range append(rules[:len(rules):len(rules)], tokens...) {
+		s := strings.Map(func(r rune) rune {
+			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+
+			return '_'
+		}, v)
+		if i < len(rules) {
+			switch r, n := utf8.DecodeRuneInString(s); {
+			case unicode.IsUpper(r):
+				// ok
+			case unicode.IsLetter(r):
+				s = string(unicode.ToUpper(r)) + s[n:]
+			default:
+				s = "X" + s
+			}
+		}
+		m[v] = s
+		switch {
+		case s == v:
+			used[s] = true
+		default:
+			renamed = append(renamed, v)
+		}
+	}
+	for _, v := range renamed {
+		s := m[v]
+		for used[s] {
+			s += "_"
+		}
+		m[v] = s
+		used[s] = true
+	}
+	return m
+}
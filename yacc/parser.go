@@ -281,6 +281,10 @@ func (e errList) Error() string {
 	return strings.Join(a, "\n")
 }
 
+// Unwrap returns the individual errors. It makes the list usable with
+// errors.Is and errors.As.
+func (e errList) Unwrap() []error { return e }
+
 func lx(yylex yyLexer) *lexer {
 	return yylex.(*lexer)
 }